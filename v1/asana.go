@@ -82,10 +82,6 @@ func (c *Client) httpClient() *http.Client {
 	return &http.Client{Transport: rt}
 }
 
-type User struct {
-	UID UserID `json:"user"`
-}
-
 type UserID string
 
 var _ json.Marshaler = (*UserID)(nil)
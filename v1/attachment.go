@@ -20,8 +20,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"mime/multipart"
 	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/orijtech/otils"
@@ -30,7 +35,8 @@ import (
 )
 
 type Attachment struct {
-	ID int64 `json:"id,omitempty"`
+	ID  int64  `json:"id,omitempty"`
+	GID string `json:"gid,omitempty"`
 
 	CreatedAt   *otils.NullableTime  `json:"created_at,omitempty"`
 	DownloadURL otils.NullableString `json:"download_url"`
@@ -41,10 +47,14 @@ type Attachment struct {
 
 	Name otils.NullableString `json:"name"`
 
-	// Parent contains the information of the
-	// task that this attachment is attached to.
+	// Parent contains the information of the task, project
+	// or project brief that this attachment is attached to.
 	Parent *NamedAndIDdEntity `json:"parent,omitempty"`
 
+	// ResourceSubtype is "asana" for uploaded files,
+	// "external" for links and otherwise the Host.
+	ResourceSubtype string `json:"resource_subtype,omitempty"`
+
 	ViewURL otils.NullableString `json:"view_url,omitempty"`
 }
 
@@ -83,10 +93,49 @@ func parseOutAttachmentFromData(blob []byte) (*Attachment, error) {
 	return nil, errNoAttachment
 }
 
+// MaxAttachmentSize is the largest file in bytes that Asana accepts.
+const MaxAttachmentSize = 100 << 20
+
+// ErrAttachmentTooLarge is returned when uploading a
+// file that is larger than MaxAttachmentSize.
+var ErrAttachmentTooLarge = fmt.Errorf("attachments cannot be larger than %d bytes", MaxAttachmentSize)
+
 type AttachmentUpload struct {
-	Body   io.Reader `json:"-"`
-	TaskID string    `json:"task_id"`
-	Name   string    `json:"name"`
+	Body io.Reader `json:"-"`
+
+	// ParentID is the gid of the task, project or project
+	// brief to attach to. It takes precedence over TaskID.
+	ParentID string `json:"parent,omitempty"`
+
+	// TaskID is the id of the task to attach to,
+	// used if ParentID is blank.
+	TaskID string `json:"task_id"`
+
+	Name string `json:"name"`
+
+	// ContentType if set, is sent instead of the
+	// type detected from the first bytes of Body.
+	ContentType string `json:"-"`
+
+	// Size if set, is the number of bytes in Body. Otherwise it
+	// is looked up for files, seekers and in-memory readers. A
+	// known size is checked against MaxAttachmentSize before
+	// uploading, an unknown one once that many bytes were read.
+	Size int64 `json:"-"`
+
+	// Progress if set, is invoked as Body is uploaded with the
+	// number of bytes sent so far and the Size, or -1 if unknown.
+	// It is called on the goroutine that writes the multipart
+	// body rather than the one calling UploadAttachment, so any
+	// state that it shares with the caller must be synchronized.
+	Progress func(sent, total int64) `json:"-"`
+}
+
+func (au *AttachmentUpload) parentID() string {
+	if parentID := strings.TrimSpace(au.ParentID); parentID != "" {
+		return parentID
+	}
+	return strings.TrimSpace(au.TaskID)
 }
 
 func (au *AttachmentUpload) nonBlankFilename() string {
@@ -96,97 +145,330 @@ func (au *AttachmentUpload) nonBlankFilename() string {
 	return uuid.NewRandom().String()
 }
 
-var errNilBody = errors.New("expecting a non-nil body")
+var (
+	errNilBody     = errors.New("expecting a non-nil body")
+	errEmptyParent = errors.New("expecting a non-empty parentID or taskID")
+)
 
 func (au *AttachmentUpload) Validate() error {
 	if au == nil || au.Body == nil {
 		return errNilBody
 	}
-	if strings.TrimSpace(au.TaskID) == "" {
-		return errEmptyTaskID
+	if au.parentID() == "" {
+		return errEmptyParent
+	}
+	if au.Size > MaxAttachmentSize {
+		return ErrAttachmentTooLarge
 	}
 	return nil
 }
 
-// UploadAtatchment uploads an attachment to a specific task.
-// Its fields: TaskID and Body must be set otherwise it will return an error.
+// UploadAtatchment uploads an attachment to a task, project or project brief.
+// Its fields: Body and either ParentID or TaskID must be set otherwise it
+// will return an error.
 func (c *Client) UploadAttachment(au *AttachmentUpload) (*Attachment, error) {
 	if err := au.Validate(); err != nil {
 		return nil, err
 	}
 
-	// Step 1. Try to determine the contentType.
+	// Step 1. Check the size before sending anything.
+	size := au.Size
+	if size <= 0 {
+		size = readerSize(au.Body)
+	}
+	if size > MaxAttachmentSize {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	// Step 2. Try to determine the contentType.
 	contentType, body, err := fDetectContentType(au.Body)
 	if err != nil {
 		return nil, err
 	}
+	if au.ContentType != "" {
+		contentType = au.ContentType
+	}
 
-	// Step 2:
+	// Step 3:
 	// Initiate and then make the upload.
 	prc, pwc := io.Pipe()
 	mpartW := multipart.NewWriter(pwc)
+	src := &uploadReader{r: body, total: size, progress: au.Progress}
+	writeErrChan := make(chan error, 1)
 	go func() {
-		defer func() {
-			_ = mpartW.Close()
-			_ = pwc.Close()
-		}()
-
-		formFile, err := mpartW.CreateFormFile("file", au.nonBlankFilename())
-		if err != nil {
-			return
+		err := writeAttachmentParts(mpartW, au, contentType, src)
+		if err == nil {
+			err = mpartW.Close()
 		}
-		_, _ = io.Copy(formFile, body)
-
-		writeStringField(mpartW, "Content-Type", contentType)
-		writeStringField(mpartW, "name", au.Name)
+		writeErrChan <- err
+		_ = pwc.CloseWithError(err)
 	}()
 
-	fullURL := fmt.Sprintf("%s/tasks/%s/attachments", baseURL, au.TaskID)
+	fullURL := fmt.Sprintf("%s/attachments", baseURL)
 	req, err := http.NewRequest("POST", fullURL, prc)
 	if err != nil {
+		_ = prc.Close()
 		return nil, err
 	}
 	req.Header.Set("Content-Type", mpartW.FormDataContentType())
 	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+
+	// Unblock the writer in case the body was not read
+	// to the end, then prefer its error since a failure
+	// to produce the body is the root cause of any other.
+	_ = prc.Close()
+	if writeErr := <-writeErrChan; writeErr != nil && writeErr != io.ErrClosedPipe {
+		return nil, writeErr
+	}
 	if err != nil {
 		return nil, err
 	}
 	return parseOutAttachmentFromData(slurp)
 }
 
+// UploadAttachmentFromFile uploads the file at path to the task, project
+// or project brief with parentID. The attachment is named after the file
+// and its content type is the one for the file's extension. For any other
+// settings, open the file and pass it as the Body to UploadAttachment.
+func (c *Client) UploadAttachmentFromFile(parentID, path string) (*Attachment, error) {
+	parentID = strings.TrimSpace(parentID)
+	if parentID == "" {
+		return nil, errEmptyParent
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return c.UploadAttachment(&AttachmentUpload{
+		ParentID:    parentID,
+		Body:        f,
+		Size:        fi.Size(),
+		Name:        filepath.Base(path),
+		ContentType: mime.TypeByExtension(filepath.Ext(path)),
+	})
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func writeAttachmentParts(w *multipart.Writer, au *AttachmentUpload, contentType string, body io.Reader) error {
+	fields := []struct{ key, value string }{
+		{"parent", au.parentID()},
+		{"resource_subtype", "asana"},
+		{"name", au.Name},
+	}
+	for _, field := range fields {
+		if err := writeStringField(w, field.key, field.value); err != nil {
+			return err
+		}
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition",
+		fmt.Sprintf(`form-data; name="file"; filename="%s"`, quoteEscaper.Replace(au.nonBlankFilename())))
+	header.Set("Content-Type", contentType)
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(part, body)
+	return err
+}
+
+// readerSize returns the number of bytes left in r or -1 if unknown.
+func readerSize(r io.Reader) int64 {
+	switch r := r.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len())
+	case io.Seeker:
+		cur, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		end, err := r.Seek(0, io.SeekEnd)
+		if err != nil {
+			return -1
+		}
+		if _, err := r.Seek(cur, io.SeekStart); err != nil {
+			return -1
+		}
+		return end - cur
+	}
+	return -1
+}
+
+// uploadReader reports the progress of an upload and
+// stops it once more than MaxAttachmentSize was read.
+type uploadReader struct {
+	r        io.Reader
+	sent     int64
+	total    int64
+	progress func(sent, total int64)
+}
+
+func (ur *uploadReader) Read(b []byte) (int, error) {
+	n, err := ur.r.Read(b)
+	if n > 0 {
+		ur.sent += int64(n)
+		if ur.sent > MaxAttachmentSize {
+			return n, ErrAttachmentTooLarge
+		}
+		if ur.progress != nil {
+			ur.progress(ur.sent, ur.total)
+		}
+	}
+	return n, err
+}
+
 type AttachmentsPage struct {
 	Attachments []*Attachment `json:"data"`
 }
 
+type attachmentsPager struct {
+	AttachmentsPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
 // ListAllAttachmentsForTask retrieves all the attachments for the taskID provided.
 func (c *Client) ListAllAttachmentsForTask(taskID string) (*AttachmentsPage, error) {
 	taskID = strings.TrimSpace(taskID)
 	if taskID == "" {
 		return nil, errEmptyTaskID
 	}
-	fullURL := fmt.Sprintf("%s/tasks/%s/attachments", baseURL, taskID)
-	req, err := http.NewRequest("GET", fullURL, nil)
+	return c.ListAllAttachmentsForParent(taskID)
+}
+
+// ListAllAttachmentsForParent retrieves all the attachments of
+// the task, project or project brief with the parentID provided,
+// following the pages of the results until the last one.
+func (c *Client) ListAllAttachmentsForParent(parentID string) (*AttachmentsPage, error) {
+	parentID = strings.TrimSpace(parentID)
+	if parentID == "" {
+		return nil, errEmptyParent
+	}
+
+	qs := url.Values{"parent": {parentID}}
+	path := fmt.Sprintf("/attachments?%s", qs.Encode())
+	apage := new(AttachmentsPage)
+	err := c.pageThrough(path, nil, func(slurp []byte) (string, error) {
+		pager := new(attachmentsPager)
+		if err := json.Unmarshal(slurp, pager); err != nil {
+			return "", err
+		}
+		apage.Attachments = append(apage.Attachments, pager.Attachments...)
+		return pager.NextPage.nextPath(), nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	return apage, nil
+}
 
-	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+func (c *Client) DeleteAttachment(attachmentID string) error {
+	attachmentID = strings.TrimSpace(attachmentID)
+	if attachmentID == "" {
+		return errEmptyAttachmentID
+	}
+	fullURL := fmt.Sprintf("%s/attachments/%s", baseURL, attachmentID)
+	req, err := http.NewRequest("DELETE", fullURL, nil)
 	if err != nil {
+		return err
+	}
+	_, _, err = c.doAuthReqThenSlurpBody(req)
+	return err
+}
+
+// ExternalAttachmentRequest links a URL, such as a design
+// doc or a CI artifact, to a parent without uploading it.
+type ExternalAttachmentRequest struct {
+	// ParentID is the gid of the task, project
+	// or project brief to attach to.
+	ParentID string
+
+	// URL must be an absolute http or https URL.
+	URL string
+
+	// Name is required and is shown instead of the URL.
+	Name string
+}
+
+var (
+	errNilExternalAttachment = errors.New("expecting a non-nil externalAttachmentRequest")
+	errEmptyAttachmentURL    = errors.New("expecting a non-empty URL")
+	errInvalidAttachmentURL  = errors.New("expecting an absolute http or https URL")
+	errEmptyAttachmentName   = errors.New("expecting a non-empty name")
+)
+
+func (ear *ExternalAttachmentRequest) Validate() error {
+	if ear == nil {
+		return errNilExternalAttachment
+	}
+	if strings.TrimSpace(ear.ParentID) == "" {
+		return errEmptyParent
+	}
+	rawURL := strings.TrimSpace(ear.URL)
+	if rawURL == "" {
+		return errEmptyAttachmentURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return errInvalidAttachmentURL
+	}
+	if strings.TrimSpace(ear.Name) == "" {
+		return errEmptyAttachmentName
+	}
+	return nil
+}
+
+// CreateExternalAttachment attaches a link to a task, project or project brief.
+func (c *Client) CreateExternalAttachment(ear *ExternalAttachmentRequest) (*Attachment, error) {
+	if err := ear.Validate(); err != nil {
 		return nil, err
 	}
 
-	apage := new(AttachmentsPage)
-	if err := json.Unmarshal(slurp, apage); err != nil {
+	buf := new(bytes.Buffer)
+	mpartW := multipart.NewWriter(buf)
+	fields := []struct{ key, value string }{
+		{"parent", strings.TrimSpace(ear.ParentID)},
+		{"resource_subtype", "external"},
+		{"url", strings.TrimSpace(ear.URL)},
+		{"name", ear.Name},
+	}
+	for _, field := range fields {
+		if err := writeStringField(mpartW, field.key, field.value); err != nil {
+			return nil, err
+		}
+	}
+	if err := mpartW.Close(); err != nil {
 		return nil, err
 	}
-	return apage, nil
+
+	fullURL := fmt.Sprintf("%s/attachments", baseURL)
+	req, err := http.NewRequest("POST", fullURL, buf)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", mpartW.FormDataContentType())
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutAttachmentFromData(slurp)
 }
 
-func writeStringField(w *multipart.Writer, key, value string) {
+func writeStringField(w *multipart.Writer, key, value string) error {
 	fw, err := w.CreateFormField(key)
-	if err == nil {
-		_, _ = io.WriteString(fw, value)
+	if err != nil {
+		return err
 	}
+	_, err = io.WriteString(fw, value)
+	return err
 }
 
 func fDetectContentType(r io.Reader) (string, io.Reader, error) {
@@ -201,6 +483,7 @@ func fDetectContentType(r io.Reader) (string, io.Reader, error) {
 		return "", nil, err
 	}
 
+	sniffBuf = sniffBuf[:n]
 	contentType := http.DetectContentType(sniffBuf)
 	needsRepad := !seekable
 	if seekable {
@@ -0,0 +1,281 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/orijtech/otils"
+)
+
+var (
+	errNoDownloadURL  = errors.New("attachment has no download URL")
+	errNegativeOffset = errors.New("expecting a non-negative offset")
+	errEmptyMirrorDir = errors.New("expecting a non-empty directory")
+	errRangeIgnored   = errors.New("server ignored the range request")
+)
+
+// maxDownloadErrBody bounds how much of a failed
+// download's body is read into the error.
+const maxDownloadErrBody = 4 << 10
+
+// AttachmentContent is the body of a downloaded attachment.
+// It must be closed after use.
+type AttachmentContent struct {
+	io.ReadCloser
+
+	ContentType string
+
+	// ContentLength is the number of bytes that Read will
+	// return, starting from Offset. It is -1 if unknown.
+	ContentLength int64
+
+	// Offset is the position in the attachment that the content
+	// starts at. It is only non-zero for resumed downloads.
+	Offset int64
+
+	// size is the full size of the attachment as reported
+	// in the Content-Range of a resumed download, if any.
+	size int64
+}
+
+// Size returns the full size of the attachment
+// or -1 if the server did not report it.
+func (ac *AttachmentContent) Size() int64 {
+	if ac.size > 0 {
+		return ac.size
+	}
+	if ac.ContentLength < 0 {
+		return -1
+	}
+	return ac.Offset + ac.ContentLength
+}
+
+// GIDOrID returns the gid of the attachment, falling back to its numeric id.
+func (a *Attachment) GIDOrID() string {
+	if a.GID != "" {
+		return a.GID
+	}
+	if a.ID != 0 {
+		return strconv.FormatInt(a.ID, 10)
+	}
+	return ""
+}
+
+// DownloadAttachment streams the content of an attachment. Download
+// URLs expire shortly after they are handed out so the attachment is
+// looked up afresh before every download. The returned io.ReadCloser
+// is an *AttachmentContent which reports the content type and length.
+func (c *Client) DownloadAttachment(attachmentID string) (io.ReadCloser, *Attachment, error) {
+	content, attachment, err := c.DownloadAttachmentFrom(attachmentID, 0)
+	if err != nil {
+		return nil, attachment, err
+	}
+	return content, attachment, nil
+}
+
+// DownloadAttachmentFrom is like DownloadAttachment but starts at
+// offset bytes into the attachment, for resuming an interrupted
+// download. If the host does not support range requests it returns
+// an error rather than the content from the beginning.
+func (c *Client) DownloadAttachmentFrom(attachmentID string, offset int64) (*AttachmentContent, *Attachment, error) {
+	if offset < 0 {
+		return nil, nil, errNegativeOffset
+	}
+	attachment, err := c.FindAttachmentByID(attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	downloadURL := strings.TrimSpace(string(attachment.DownloadURL))
+	if downloadURL == "" {
+		return nil, attachment, errNoDownloadURL
+	}
+
+	req, err := http.NewRequest("GET", downloadURL, nil)
+	if err != nil {
+		return nil, attachment, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	// The download URL is pre-signed and usually served by
+	// another host so the access token must not be sent along.
+	res, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, attachment, err
+	}
+	if !otils.StatusOK(res.StatusCode) {
+		defer res.Body.Close()
+		errMsg := res.Status
+		if slurp, _ := ioutil.ReadAll(io.LimitReader(res.Body, maxDownloadErrBody)); len(slurp) > 0 {
+			errMsg = string(slurp)
+		}
+		return nil, attachment, &HTTPError{msg: errMsg, code: res.StatusCode}
+	}
+	if offset > 0 && res.StatusCode != http.StatusPartialContent {
+		res.Body.Close()
+		return nil, attachment, errRangeIgnored
+	}
+
+	content := &AttachmentContent{
+		ReadCloser:    res.Body,
+		ContentType:   res.Header.Get("Content-Type"),
+		ContentLength: res.ContentLength,
+		Offset:        offset,
+		size:          contentRangeSize(res.Header.Get("Content-Range")),
+	}
+	return content, attachment, nil
+}
+
+// contentRangeSize returns the full size from a Content-Range
+// header such as "bytes 100-199/200" or 0 if it is unknown.
+func contentRangeSize(contentRange string) int64 {
+	i := strings.LastIndex(contentRange, "/")
+	if i < 0 {
+		return 0
+	}
+	size, err := strconv.ParseInt(contentRange[i+1:], 10, 64)
+	if err != nil || size < 0 {
+		return 0
+	}
+	return size
+}
+
+// MirrorTaskAttachments downloads every attachment of a task into dir,
+// which is created if needed, and returns the paths of the files. Each
+// file is named after its attachment. Downloads are first written to a
+// ".part" file which is renamed once complete so that an interrupted
+// download from an earlier run is resumed and files that are as large
+// as their attachments are left as they are.
+// Attachments without a download URL, such as links, are skipped.
+func (c *Client) MirrorTaskAttachments(taskID, dir string) ([]string, error) {
+	if strings.TrimSpace(dir) == "" {
+		return nil, errEmptyMirrorDir
+	}
+	apage, err := c.ListAllAttachmentsForTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	seen := make(map[string]bool)
+	for _, attachment := range apage.Attachments {
+		if attachment == nil {
+			continue
+		}
+		attachmentID := attachment.GIDOrID()
+		if attachmentID == "" {
+			continue
+		}
+		filename := mirrorFilename(attachmentID, string(attachment.Name))
+		if seen[filename] {
+			filename = attachmentID + "-" + filename
+		}
+		seen[filename] = true
+
+		path := filepath.Join(dir, filename)
+		err := c.mirrorAttachment(attachmentID, path)
+		if err == errNoDownloadURL {
+			continue
+		}
+		if err != nil {
+			return paths, fmt.Errorf("attachment %s: %v", attachmentID, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+func mirrorFilename(attachmentID, name string) string {
+	name = filepath.Base(strings.TrimSpace(name))
+	switch name {
+	case "", ".", "..", string(filepath.Separator):
+		return attachmentID
+	}
+	return name
+}
+
+func (c *Client) mirrorAttachment(attachmentID, path string) error {
+	partPath := path + ".part"
+
+	// A file at path is only trusted if it is as large as the attachment,
+	// otherwise it is downloaded afresh. Checking that requires the size
+	// so the download starts from the beginning in either case.
+	finalSize, partSize := int64(-1), int64(0)
+	if fi, err := os.Stat(path); err == nil {
+		finalSize = fi.Size()
+	} else if fi, err := os.Stat(partPath); err == nil {
+		partSize = fi.Size()
+	}
+
+	offset := partSize
+	content, _, err := c.DownloadAttachmentFrom(attachmentID, offset)
+	if err == errRangeIgnored || isRangeNotSatisfiable(err) {
+		// The host cannot resume downloads or the partial file
+		// is at least as large as the attachment: start over.
+		offset = 0
+		content, _, err = c.DownloadAttachmentFrom(attachmentID, offset)
+	}
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	size := content.Size()
+	if size >= 0 && size == finalSize {
+		return nil
+	}
+	if offset == 0 && size > 0 && size == partSize {
+		// Complete but never renamed.
+		return os.Rename(partPath, path)
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if offset == 0 {
+		flags |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(f, content)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if size >= 0 && offset+n != size {
+		return io.ErrUnexpectedEOF
+	}
+	return os.Rename(partPath, path)
+}
+
+func isRangeNotSatisfiable(err error) bool {
+	herr, ok := err.(*HTTPError)
+	return ok && herr.Code() == http.StatusRequestedRangeNotSatisfiable
+}
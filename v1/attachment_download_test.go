@@ -0,0 +1,233 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orijtech/asana/v1"
+)
+
+const (
+	filesHost = "files.example.com"
+
+	// staticHost does not support range requests.
+	staticHost = "static.example.com"
+)
+
+var attachmentContents = map[string]string{
+	"5678": "\x89PNG\r\n\x1a\n the rest of the background image",
+	"9012": "%PDF-1.4 the new design draft",
+}
+
+func TestDownloadAttachment(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: downloadAttachmentRoute})
+
+	png := attachmentContents["5678"]
+	tests := [...]struct {
+		attachmentID string
+		offset       int64
+		wantBody     string
+		wantType     string
+		wantSize     int64
+		wantErr      bool
+	}{
+		0: {attachmentID: "", wantErr: true},
+		1: {attachmentID: "5678", offset: -1, wantErr: true},
+		2: {attachmentID: "5678", wantBody: png, wantType: "image/png", wantSize: int64(len(png))},
+		3: {attachmentID: "5678", offset: 8, wantBody: png[8:], wantType: "image/png", wantSize: int64(len(png))},
+		4: {attachmentID: "5678", offset: int64(len(png)) + 10, wantErr: true},
+
+		// An external link has no download URL.
+		5: {attachmentID: "3456", wantErr: true},
+
+		// The host does not support range requests.
+		6: {attachmentID: "7890", offset: 4, wantErr: true},
+	}
+
+	for i, tt := range tests {
+		content, attachment, err := client.DownloadAttachmentFrom(tt.attachmentID, tt.offset)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		body, err := ioutil.ReadAll(content)
+		content.Close()
+		if err != nil {
+			t.Errorf("#%d: reading the content: %v", i, err)
+			continue
+		}
+		if string(body) != tt.wantBody {
+			t.Errorf("#%d: gotBody=%q wantBody=%q", i, body, tt.wantBody)
+		}
+		if content.ContentType != tt.wantType {
+			t.Errorf("#%d: gotType=%q wantType=%q", i, content.ContentType, tt.wantType)
+		}
+		if got := content.Size(); got != tt.wantSize {
+			t.Errorf("#%d: gotSize=%d wantSize=%d", i, got, tt.wantSize)
+		}
+		if attachment.GIDOrID() != tt.attachmentID {
+			t.Errorf("#%d: got attachment %s", i, jsonMarshal(attachment))
+		}
+	}
+
+	rc, _, err := client.DownloadAttachment("5678")
+	if err != nil {
+		t.Fatalf("downloading: %v", err)
+	}
+	defer rc.Close()
+	if content, ok := rc.(*asana.AttachmentContent); !ok || content.ContentLength != int64(len(png)) {
+		t.Errorf("expected an *AttachmentContent with the content length, got %#v", rc)
+	}
+}
+
+func TestMirrorTaskAttachments(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: downloadAttachmentRoute})
+
+	png, pdf := attachmentContents["5678"], attachmentContents["9012"]
+	tests := [...]struct {
+		// existing are the files left in the directory by an earlier run.
+		existing map[string]string
+	}{
+		0: {existing: nil},
+
+		// An interrupted download is resumed.
+		1: {existing: map[string]string{"Background.png.part": png[:10]}},
+
+		// A partial file larger than the attachment is started over.
+		2: {existing: map[string]string{"Background.png.part": png + " and then some"}},
+
+		// A complete download that was never renamed.
+		3: {existing: map[string]string{"Background.png.part": png}},
+
+		// A file that is not as large as the attachment is replaced.
+		4: {existing: map[string]string{"New Design Draft.pdf": pdf[:5]}},
+		5: {existing: map[string]string{"Background.png": png, "New Design Draft.pdf": pdf}},
+	}
+
+	for i, tt := range tests {
+		dir, err := ioutil.TempDir("", "asana-mirror")
+		if err != nil {
+			t.Fatalf("#%d: creating the directory: %v", i, err)
+		}
+		defer os.RemoveAll(dir)
+
+		for name, content := range tt.existing {
+			if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+				t.Fatalf("#%d: writing %q: %v", i, name, err)
+			}
+		}
+
+		wantPaths := []string{filepath.Join(dir, "Background.png"), filepath.Join(dir, "New Design Draft.pdf")}
+		for run := 0; run < 2; run++ {
+			paths, err := client.MirrorTaskAttachments(taskID1, dir)
+			if err != nil {
+				t.Errorf("#%d: run #%d: mirroring: %v", i, run, err)
+				continue
+			}
+			if !reflect.DeepEqual(paths, wantPaths) {
+				t.Errorf("#%d: run #%d:\ngotPaths:  %v\nwantPaths: %v", i, run, paths, wantPaths)
+			}
+			for j, want := range []string{png, pdf} {
+				got, err := ioutil.ReadFile(wantPaths[j])
+				if err != nil {
+					t.Errorf("#%d: run #%d: %v", i, run, err)
+					continue
+				}
+				if string(got) != want {
+					t.Errorf("#%d: run #%d: %s: got=%q want=%q", i, run, wantPaths[j], got, want)
+				}
+			}
+			if parts, _ := filepath.Glob(filepath.Join(dir, "*.part")); len(parts) > 0 {
+				t.Errorf("#%d: run #%d: leftover partial files: %v", i, run, parts)
+			}
+		}
+	}
+}
+
+func (b *backend) downloadAttachmentRoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host == filesHost {
+		// The access token must never be sent to the file host.
+		if req.Header.Get("Authorization") != "" {
+			return makeResp("leaked credentials", http.StatusForbidden, nil), nil
+		}
+		attachmentID := strings.TrimPrefix(req.URL.Path, "/")
+		content, ok := attachmentContents[attachmentID]
+		if !ok {
+			return unknownRouteResp, nil
+		}
+		rec := httptest.NewRecorder()
+		http.ServeContent(rec, req, "", time.Time{}, bytes.NewReader([]byte(content)))
+		return rec.Result(), nil
+	}
+	if req.URL.Host == staticHost {
+		return makeResp("200 OK", http.StatusOK, nopCloser("no ranges here")), nil
+	}
+
+	if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
+		return badAuthResp, err
+	}
+	path := strings.TrimPrefix(req.URL.Path, "/api/1.0")
+	var body string
+	switch path {
+	case "/attachments":
+		if req.URL.Query().Get("parent") != taskID1 {
+			return unknownRouteResp, nil
+		}
+		body = `{"data": [
+			{"id": 5678, "name": "Background.png"},
+			{"id": 3456, "name": "Design doc"},
+			{"id": 9012, "name": "New Design Draft.pdf"}
+		]}`
+
+	case "/attachments/5678", "/attachments/9012":
+		attachmentID := strings.TrimPrefix(path, "/attachments/")
+		body = fmt.Sprintf(`{"data": {"id": %s, "download_url": "https://%s/%s?expires=60"}}`, attachmentID, filesHost, attachmentID)
+
+	case "/attachments/3456":
+		body = `{"data": {"id": 3456, "host": "external", "download_url": null, "view_url": "https://docs.example.com/design"}}`
+
+	case "/attachments/7890":
+		body = fmt.Sprintf(`{"data": {"id": 7890, "download_url": "https://%s/7890"}}`, staticHost)
+
+	default:
+		return unknownRouteResp, nil
+	}
+	return makeResp("200 OK", http.StatusOK, nopCloser(body)), nil
+}
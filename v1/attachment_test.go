@@ -21,11 +21,14 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/orijtech/asana/v1"
+	"github.com/orijtech/otils"
 )
 
 func TestFindAttachmentByID(t *testing.T) {
@@ -59,7 +62,7 @@ func TestFindAttachmentByID(t *testing.T) {
 		attachment, err := client.FindAttachmentByID(tt.attachmentID)
 		if tt.wantErr {
 			if err == nil {
-				t.Errorf("#%d: wanted non-nil error")
+				t.Errorf("#%d: wanted non-nil error", i)
 			}
 			continue
 		}
@@ -107,7 +110,7 @@ func TestListAllAttachmentForTask(t *testing.T) {
 		attachmentsPage, err := client.ListAllAttachmentsForTask(tt.taskID)
 		if tt.wantErr {
 			if err == nil {
-				t.Errorf("#%d: wanted non-nil error")
+				t.Errorf("#%d: wanted non-nil error", i)
 			}
 			continue
 		}
@@ -165,6 +168,21 @@ func TestUploadAttachment(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		4: {
+			req: &asana.AttachmentUpload{
+				Name: "Messenger QR code",
+				Body: fFromFile("./testdata/messengerQR.png"),
+			},
+			wantErr: true,
+		},
+		5: {
+			req: &asana.AttachmentUpload{
+				ParentID: projectID1,
+				Name:     "Messenger QR code",
+				Body:     fFromFile("./testdata/messengerQR.png"),
+			},
+			want: attachmentFromFile(attachmentID1),
+		},
 	}
 
 	for i, tt := range tests {
@@ -189,15 +207,127 @@ func TestUploadAttachment(t *testing.T) {
 	}
 }
 
+func TestCreateExternalAttachment(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: attachmentsRoute})
+
+	tests := [...]struct {
+		req     *asana.ExternalAttachmentRequest
+		wantErr bool
+	}{
+		0: {req: nil, wantErr: true},
+		1: {req: &asana.ExternalAttachmentRequest{URL: "https://ci.example.com/build/42", Name: "Build 42"}, wantErr: true},
+		2: {req: &asana.ExternalAttachmentRequest{ParentID: taskID1, Name: "Build 42"}, wantErr: true},
+		3: {req: &asana.ExternalAttachmentRequest{ParentID: taskID1, URL: "ci.example.com/build/42", Name: "Build 42"}, wantErr: true},
+		4: {req: &asana.ExternalAttachmentRequest{ParentID: taskID1, URL: "ftp://ci.example.com/build/42", Name: "Build 42"}, wantErr: true},
+		5: {req: &asana.ExternalAttachmentRequest{ParentID: taskID1, URL: "https://ci.example.com/build/42", Name: " "}, wantErr: true},
+		6: {req: &asana.ExternalAttachmentRequest{ParentID: taskID1, URL: "https://ci.example.com/build/42", Name: "Build 42"}},
+		7: {req: &asana.ExternalAttachmentRequest{ParentID: projectID1, URL: "https://docs.example.com/design", Name: "Design doc"}},
+	}
+
+	for i, tt := range tests {
+		attachment, err := client.CreateExternalAttachment(tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		if attachment.ResourceSubtype != "external" || string(attachment.ViewURL) != tt.req.URL ||
+			string(attachment.Name) != tt.req.Name || attachment.Parent.GID != tt.req.ParentID {
+			t.Errorf("#%d: got attachment %s", i, jsonMarshal(attachment))
+		}
+	}
+}
+
+func TestDeleteAttachment(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: attachmentsRoute})
+
+	tests := [...]struct {
+		attachmentID string
+		wantErr      bool
+	}{
+		0: {attachmentID: "", wantErr: true},
+		1: {attachmentID: "  ", wantErr: true},
+		2: {attachmentID: "unknown", wantErr: true},
+		3: {attachmentID: attachmentID1},
+	}
+
+	for i, tt := range tests {
+		err := client.DeleteAttachment(tt.attachmentID)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+		} else if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+		}
+	}
+}
+
+func TestListAllAttachmentsForParent(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: attachmentsRoute})
+
+	if _, err := client.ListAllAttachmentsForParent(" "); err == nil {
+		t.Errorf("expected an error for an empty parentID")
+	}
+	apage, err := client.ListAllAttachmentsForParent(projectID1)
+	if err != nil {
+		t.Fatalf("listing attachments: %v", err)
+	}
+	if len(apage.Attachments) != 1 || apage.Attachments[0].GID != "brief-attachment-1" {
+		t.Errorf("got page %s", jsonMarshal(apage))
+	}
+}
+
 const (
 	paToken1 = "pa-token-1"
 
 	attachmentID1 = "5678"
 	taskID1       = "task-id-1"
+	projectID1    = "project-1"
+	workspaceID1  = "workspace-1"
+	teamID1       = "team-1"
 
 	findAttachmentByIDRoute = "find-attachment-by-id"
 	uploadAttachmentRoute   = "upload-attachment"
 	listAllAttachmentsRoute = "list-all-attachments-route"
+	queryForTasksRoute      = "query-for-tasks"
+	updateTaskRoute         = "update-task"
+	createCustomFieldRoute  = "create-custom-field"
+	sectionsRoute           = "sections"
+	storiesRoute            = "stories"
+	tagsRoute               = "tags"
+	eventsRoute             = "events"
+	statusUpdatesRoute      = "status-updates"
+	jobsRoute               = "jobs"
+	projectMembersRoute     = "project-members"
+	projectsRoute           = "projects"
+	usersRoute              = "users"
+	workspacesRoute         = "workspaces"
+	teamsRoute              = "teams"
+	userTaskListsRoute      = "user-task-lists"
+	portfoliosRoute         = "portfolios"
+	goalsRoute              = "goals"
+	timeTrackingRoute       = "time-tracking"
+	downloadAttachmentRoute = "download-attachment"
+	attachmentsRoute        = "attachments"
+	taskFormRoute           = "task-form"
 )
 
 var authorizedTokens = map[string]bool{
@@ -257,6 +387,17 @@ func jsonMarshal(v interface{}) []byte {
 
 type backend struct {
 	route string
+
+	// jobPolls counts the polls of each job.
+	jobPolls map[string]int
+
+	// uploadedContentTypes are the content
+	// types of the uploaded file parts.
+	uploadedContentTypes []string
+
+	// lastForm is the form of the last request
+	// on routes that record what was sent.
+	lastForm url.Values
 }
 
 var _ http.RoundTripper = (*backend)(nil)
@@ -269,6 +410,48 @@ func (b *backend) RoundTrip(req *http.Request) (*http.Response, error) {
 		return b.uploadAttachmentRoundTrip(req)
 	case listAllAttachmentsRoute:
 		return b.listAllAttachmentsRoundTrip(req)
+	case queryForTasksRoute:
+		return b.queryForTasksRoundTrip(req)
+	case updateTaskRoute:
+		return b.updateTaskRoundTrip(req)
+	case createCustomFieldRoute:
+		return b.createCustomFieldRoundTrip(req)
+	case sectionsRoute:
+		return b.sectionsRoundTrip(req)
+	case storiesRoute:
+		return b.storiesRoundTrip(req)
+	case tagsRoute:
+		return b.tagsRoundTrip(req)
+	case eventsRoute:
+		return b.eventsRoundTrip(req)
+	case statusUpdatesRoute:
+		return b.statusUpdatesRoundTrip(req)
+	case jobsRoute:
+		return b.jobsRoundTrip(req)
+	case projectMembersRoute:
+		return b.projectMembersRoundTrip(req)
+	case projectsRoute:
+		return b.projectsRoundTrip(req)
+	case usersRoute:
+		return b.usersRoundTrip(req)
+	case workspacesRoute:
+		return b.workspacesRoundTrip(req)
+	case teamsRoute:
+		return b.teamsRoundTrip(req)
+	case userTaskListsRoute:
+		return b.userTaskListsRoundTrip(req)
+	case portfoliosRoute:
+		return b.portfoliosRoundTrip(req)
+	case goalsRoute:
+		return b.goalsRoundTrip(req)
+	case timeTrackingRoute:
+		return b.timeTrackingRoundTrip(req)
+	case downloadAttachmentRoute:
+		return b.downloadAttachmentRoundTrip(req)
+	case attachmentsRoute:
+		return b.attachmentsRoundTrip(req)
+	case taskFormRoute:
+		return b.taskFormRoundTrip(req)
 	default:
 		return unknownRouteResp, nil
 	}
@@ -317,24 +500,38 @@ func (b *backend) checkAuthorization(req *http.Request, wantMethod string) (*htt
 	return nil, nil
 }
 
+// listAllAttachmentsRoundTrip serves the attachments
+// of a task one per page to exercise the paging.
 func (b *backend) listAllAttachmentsRoundTrip(req *http.Request) (*http.Response, error) {
 	if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
 		return badAuthResp, err
 	}
-
-	urlPath := strings.Trim(req.URL.Path, "/")
-	splits := strings.Split(urlPath, "/")
-	if len(splits) < 2 {
-		return makeResp("expecting the attachment id", http.StatusBadRequest, nil), nil
+	if req.URL.Path != "/api/1.0/attachments" {
+		return unknownRouteResp, nil
 	}
 
-	// Second last segment of the path
-	taskID := splits[len(splits)-2]
+	query := req.URL.Query()
+	taskID := query.Get("parent")
 	if taskID == "" {
-		return makeResp("expecting a taskID", http.StatusBadRequest, nil), nil
+		return makeResp("expecting a parent", http.StatusBadRequest, nil), nil
 	}
-	diskPath := allAttachmentsForTaskPath(taskID)
-	return makeRespFromFile(diskPath)
+	apage := attachmentsPageFromFile(taskID)
+	if apage == nil {
+		return unknownRouteResp, nil
+	}
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	if offset < 0 || offset >= len(apage.Attachments) {
+		return makeResp("invalid offset", http.StatusBadRequest, nil), nil
+	}
+	page := map[string]interface{}{"data": apage.Attachments[offset : offset+1]}
+	if next := offset + 1; next < len(apage.Attachments) {
+		nextQuery := url.Values{"parent": {taskID}, "offset": {strconv.Itoa(next)}}
+		page["next_page"] = map[string]string{
+			"offset": strconv.Itoa(next),
+			"path":   "/attachments?" + nextQuery.Encode(),
+		}
+	}
+	return makeResp("200 OK", http.StatusOK, nopCloser(string(jsonMarshal(page)))), nil
 }
 
 func (b *backend) uploadAttachmentRoundTrip(req *http.Request) (*http.Response, error) {
@@ -346,16 +543,11 @@ func (b *backend) uploadAttachmentRoundTrip(req *http.Request) (*http.Response,
 		return makeResp(err.Error(), http.StatusBadRequest, nil), nil
 	}
 
-	urlPath := strings.Trim(req.URL.Path, "/")
-	splits := strings.Split(urlPath, "/")
-	if len(splits) < 2 {
-		return makeResp("expecting the attachment id", http.StatusBadRequest, nil), nil
+	if req.URL.Path != "/api/1.0/attachments" {
+		return unknownRouteResp, nil
 	}
-
-	// Second last segment of the path
-	taskID := splits[len(splits)-2]
-	if taskID == "" {
-		return makeResp("expecting a taskID", http.StatusBadRequest, nil), nil
+	if req.FormValue("parent") == "" {
+		return makeResp("expecting a parent", http.StatusBadRequest, nil), nil
 	}
 
 	// Enforce that the name is sent
@@ -364,11 +556,16 @@ func (b *backend) uploadAttachmentRoundTrip(req *http.Request) (*http.Response,
 		return makeResp("\"name\" should have been set", http.StatusBadRequest, nil), nil
 	}
 
-	file, _, err := req.FormFile("file")
+	if req.FormValue("Content-Type") != "" {
+		return makeResp("the content type belongs in the file part", http.StatusBadRequest, nil), nil
+	}
+
+	file, fileHeader, err := req.FormFile("file")
 	if err != nil {
 		return makeResp(err.Error(), http.StatusBadRequest, nil), nil
 	}
 	defer file.Close()
+	b.uploadedContentTypes = append(b.uploadedContentTypes, fileHeader.Header.Get("Content-Type"))
 
 	n, err := io.Copy(ioutil.Discard, file)
 	if err != nil {
@@ -383,6 +580,56 @@ func (b *backend) uploadAttachmentRoundTrip(req *http.Request) (*http.Response,
 	return makeRespFromFile(diskPath)
 }
 
+func (b *backend) attachmentsRoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case "DELETE":
+		if badAuthResp, err := b.checkAuthorization(req, "DELETE"); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		if req.URL.Path != "/api/1.0/attachments/"+attachmentID1 {
+			return unknownRouteResp, nil
+		}
+		return makeResp("200 OK", http.StatusOK, nopCloser(`{"data": {}}`)), nil
+
+	case "GET":
+		if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		if req.URL.Path != "/api/1.0/attachments" || req.URL.Query().Get("parent") != projectID1 {
+			return unknownRouteResp, nil
+		}
+		body := `{"data": [{"gid": "brief-attachment-1", "name": "Kickoff.pdf", "resource_subtype": "asana"}]}`
+		return makeResp("200 OK", http.StatusOK, nopCloser(body)), nil
+	}
+
+	if badAuthResp, err := b.checkAuthorization(req, "POST"); err != nil || badAuthResp != nil {
+		return badAuthResp, err
+	}
+	if req.URL.Path != "/api/1.0/attachments" {
+		return unknownRouteResp, nil
+	}
+	if err := req.ParseMultipartForm(1 << 20); err != nil {
+		return makeResp(err.Error(), http.StatusBadRequest, nil), nil
+	}
+	if req.FormValue("resource_subtype") != "external" {
+		return makeResp("expecting an external attachment", http.StatusBadRequest, nil), nil
+	}
+	for _, key := range []string{"parent", "url", "name"} {
+		if req.FormValue(key) == "" {
+			return makeResp(fmt.Sprintf("%q should have been set", key), http.StatusBadRequest, nil), nil
+		}
+	}
+	attachment := &asana.Attachment{
+		GID:             "external-1",
+		Host:            "external",
+		Name:            otils.NullableString(req.FormValue("name")),
+		Parent:          &asana.NamedAndIDdEntity{GID: req.FormValue("parent")},
+		ResourceSubtype: "external",
+		ViewURL:         otils.NullableString(req.FormValue("url")),
+	}
+	return makeResp("200 OK", http.StatusOK, nopCloser(fmt.Sprintf(`{"data": %s}`, jsonMarshal(attachment)))), nil
+}
+
 func (b *backend) findAttachmentByIDRoundTrip(req *http.Request) (*http.Response, error) {
 	if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
 		return badAuthResp, err
@@ -400,6 +647,10 @@ func (b *backend) findAttachmentByIDRoundTrip(req *http.Request) (*http.Response
 	return makeRespFromFile(diskPath)
 }
 
+func nopCloser(body string) io.ReadCloser {
+	return ioutil.NopCloser(strings.NewReader(body))
+}
+
 func makeRespFromFile(path string) (*http.Response, error) {
 	f, err := os.Open(path)
 	if err != nil {
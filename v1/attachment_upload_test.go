@@ -0,0 +1,196 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/orijtech/asana/v1"
+)
+
+var errUnexpectedRead = errors.New("the body should not have been read")
+
+type unreadableReaderAt struct{}
+
+func (unreadableReaderAt) ReadAt(b []byte, off int64) (int, error) {
+	return 0, errUnexpectedRead
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = 0
+	}
+	return len(b), nil
+}
+
+var errDiskFailure = errors.New("disk failure")
+
+// failingReader fails after returning its content.
+type failingReader struct {
+	r io.Reader
+}
+
+func (fr *failingReader) Read(b []byte) (int, error) {
+	n, err := fr.r.Read(b)
+	if err == io.EOF {
+		err = errDiskFailure
+	}
+	return n, err
+}
+
+func TestUploadAttachmentFailures(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: uploadAttachmentRoute})
+
+	tests := [...]struct {
+		req     *asana.AttachmentUpload
+		wantErr error
+	}{
+		// The size of seekers is checked before reading them.
+		0: {
+			req: &asana.AttachmentUpload{
+				TaskID: taskID1,
+				Name:   "backup.tar",
+				Body:   io.NewSectionReader(unreadableReaderAt{}, 0, asana.MaxAttachmentSize+1),
+			},
+			wantErr: asana.ErrAttachmentTooLarge,
+		},
+		1: {
+			req: &asana.AttachmentUpload{
+				TaskID: taskID1,
+				Name:   "backup.tar",
+				Body:   strings.NewReader("small"),
+				Size:   asana.MaxAttachmentSize + 1,
+			},
+			wantErr: asana.ErrAttachmentTooLarge,
+		},
+		// Streams of an unknown size are stopped once they are too large.
+		2: {
+			req: &asana.AttachmentUpload{
+				TaskID: taskID1,
+				Name:   "backup.tar",
+				Body:   io.LimitReader(zeroReader{}, asana.MaxAttachmentSize+1),
+			},
+			wantErr: asana.ErrAttachmentTooLarge,
+		},
+		3: {
+			req: &asana.AttachmentUpload{
+				TaskID: taskID1,
+				Name:   "notes.txt",
+				Body:   &failingReader{r: strings.NewReader(strings.Repeat("meeting notes\n", 100))},
+			},
+			wantErr: errDiskFailure,
+		},
+	}
+
+	for i, tt := range tests {
+		_, err := client.UploadAttachment(tt.req)
+		if err != tt.wantErr {
+			t.Errorf("#%d: gotErr=%v wantErr=%v", i, err, tt.wantErr)
+		}
+	}
+}
+
+func TestUploadAttachmentContentType(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	b := &backend{route: uploadAttachmentRoute}
+	client.SetHTTPRoundTripper(b)
+
+	uploads := []*asana.AttachmentUpload{
+		0: {TaskID: taskID1, Name: "QR", Body: fFromFile("./testdata/messengerQR.png")},
+		1: {TaskID: taskID1, Name: "QR", Body: fFromFile("./testdata/messengerQR.png"), ContentType: "application/vnd.messenger.qr"},
+		2: {TaskID: taskID1, Name: "notes", Body: strings.NewReader("meeting notes: ship the uploads")},
+	}
+	for i, au := range uploads {
+		if _, err := client.UploadAttachment(au); err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+		}
+	}
+	if _, err := client.UploadAttachmentFromFile(projectID1, "./testdata/messengerQR.png"); err != nil {
+		t.Errorf("uploading from a file: %v", err)
+	}
+	if _, err := client.UploadAttachmentFromFile(projectID1, "./testdata/non-existent.png"); err == nil {
+		t.Errorf("expected an error for a non-existent file")
+	}
+	if _, err := client.UploadAttachmentFromFile(" ", "./testdata/messengerQR.png"); err == nil {
+		t.Errorf("expected an error for a blank parentID")
+	}
+
+	want := []string{"image/png", "application/vnd.messenger.qr", "text/plain; charset=utf-8", "image/png"}
+	if !reflect.DeepEqual(b.uploadedContentTypes, want) {
+		t.Errorf("\ngotContentTypes:  %q\nwantContentTypes: %q", b.uploadedContentTypes, want)
+	}
+}
+
+func TestUploadAttachmentProgress(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: uploadAttachmentRoute})
+
+	f, err := os.Open("./testdata/messengerQR.png")
+	if err != nil {
+		t.Fatalf("opening the file: %v", err)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+
+	var lastSent, lastTotal int64
+	calls := 0
+	progress := func(sent, total int64) {
+		if sent < lastSent {
+			t.Errorf("progress went backwards from %d to %d", lastSent, sent)
+		}
+		lastSent, lastTotal = sent, total
+		calls++
+	}
+
+	if _, err := client.UploadAttachment(&asana.AttachmentUpload{TaskID: taskID1, Name: "QR", Body: f, Progress: progress}); err != nil {
+		t.Fatalf("uploading: %v", err)
+	}
+	if calls == 0 {
+		t.Fatalf("progress was never reported")
+	}
+	if lastSent != fi.Size() || lastTotal != fi.Size() {
+		t.Errorf("gotSent=%d gotTotal=%d wantBoth=%d", lastSent, lastTotal, fi.Size())
+	}
+
+	// The total of streams is unknown.
+	lastSent, calls = 0, 0
+	body := io.MultiReader(strings.NewReader("meeting notes: "), strings.NewReader("ship the uploads"))
+	if _, err := client.UploadAttachment(&asana.AttachmentUpload{TaskID: taskID1, Name: "notes", Body: body, Progress: progress}); err != nil {
+		t.Fatalf("uploading a stream: %v", err)
+	}
+	if lastSent != 31 || lastTotal != -1 {
+		t.Errorf("gotSent=%d gotTotal=%d wantSent=31 wantTotal=-1", lastSent, lastTotal)
+	}
+}
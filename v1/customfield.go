@@ -0,0 +1,683 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/orijtech/otils"
+)
+
+type CustomFieldType string
+
+const (
+	CustomFieldText      CustomFieldType = "text"
+	CustomFieldNumber    CustomFieldType = "number"
+	CustomFieldEnum      CustomFieldType = "enum"
+	CustomFieldMultiEnum CustomFieldType = "multi_enum"
+	CustomFieldDate      CustomFieldType = "date"
+	CustomFieldPeople    CustomFieldType = "people"
+)
+
+type EnumOption struct {
+	ID      int64  `json:"id,omitempty"`
+	GID     string `json:"gid,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Enabled bool   `json:"enabled,omitempty"`
+	Color   string `json:"color,omitempty"`
+}
+
+// CustomFieldDateValue is the value of a date custom field.
+// DateTime is only set if the value includes a time.
+type CustomFieldDateValue struct {
+	Date     *Date      `json:"date,omitempty"`
+	DateTime *time.Time `json:"date_time,omitempty"`
+}
+
+// CustomField is a custom field and, when read
+// off a Task, the value that it has on that task.
+type CustomField struct {
+	ID   int64           `json:"id,omitempty"`
+	GID  string          `json:"gid,omitempty"`
+	Name string          `json:"name,omitempty"`
+	Type CustomFieldType `json:"resource_subtype,omitempty"`
+
+	TextValue       *string               `json:"text_value,omitempty"`
+	NumberValue     *float64              `json:"number_value,omitempty"`
+	EnumValue       *EnumOption           `json:"enum_value,omitempty"`
+	MultiEnumValues []*EnumOption         `json:"multi_enum_values,omitempty"`
+	DateValue       *CustomFieldDateValue `json:"date_value,omitempty"`
+	PeopleValue     []*NamedAndIDdEntity  `json:"people_value,omitempty"`
+
+	// DisplayValue is the value formatted as a
+	// string, regardless of the type of the field.
+	DisplayValue string `json:"display_value,omitempty"`
+
+	Description string             `json:"description,omitempty"`
+	Precision   int                `json:"precision,omitempty"`
+	Workspace   *NamedAndIDdEntity `json:"workspace,omitempty"`
+	EnumOptions []*EnumOption      `json:"enum_options,omitempty"`
+}
+
+func (cf *CustomField) Text() (string, bool) {
+	if cf == nil || cf.TextValue == nil {
+		return "", false
+	}
+	return *cf.TextValue, true
+}
+
+func (cf *CustomField) Number() (float64, bool) {
+	if cf == nil || cf.NumberValue == nil {
+		return 0, false
+	}
+	return *cf.NumberValue, true
+}
+
+func (cf *CustomField) Enum() (*EnumOption, bool) {
+	if cf == nil || cf.EnumValue == nil {
+		return nil, false
+	}
+	return cf.EnumValue, true
+}
+
+func (cf *CustomField) MultiEnum() ([]*EnumOption, bool) {
+	if cf == nil || len(cf.MultiEnumValues) == 0 {
+		return nil, false
+	}
+	return cf.MultiEnumValues, true
+}
+
+func (cf *CustomField) Date() (*CustomFieldDateValue, bool) {
+	if cf == nil || cf.DateValue == nil {
+		return nil, false
+	}
+	return cf.DateValue, true
+}
+
+func (cf *CustomField) People() ([]*NamedAndIDdEntity, bool) {
+	if cf == nil || len(cf.PeopleValue) == 0 {
+		return nil, false
+	}
+	return cf.PeopleValue, true
+}
+
+// CustomFieldByName returns the custom field on the task whose
+// name is name. It returns nil if the task has no such field.
+func (t *Task) CustomFieldByName(name string) *CustomField {
+	if t == nil {
+		return nil
+	}
+	for _, cf := range t.CustomFields {
+		if cf != nil && cf.Name == name {
+			return cf
+		}
+	}
+	return nil
+}
+
+// CustomFieldByGID returns the custom field on the task with the given
+// gid, which can also be the legacy numeric id. It returns nil if the
+// task has no such field.
+func (t *Task) CustomFieldByGID(gid string) *CustomField {
+	if t == nil {
+		return nil
+	}
+	for _, cf := range t.CustomFields {
+		if cf == nil {
+			continue
+		}
+		if cf.GID == gid || (cf.ID != 0 && strconv.FormatInt(cf.ID, 10) == gid) {
+			return cf
+		}
+	}
+	return nil
+}
+
+// CustomFieldValue is a value to set a custom field to.
+// It is created with one of CustomText, CustomNumber,
+// CustomEnum, CustomMultiEnum, CustomDate or CustomPeople.
+// Its zero value clears the custom field.
+type CustomFieldValue struct {
+	values []string
+	subKey string
+}
+
+func CustomText(text string) CustomFieldValue {
+	return CustomFieldValue{values: []string{text}}
+}
+
+func CustomNumber(number float64) CustomFieldValue {
+	return CustomFieldValue{values: []string{strconv.FormatFloat(number, 'f', -1, 64)}}
+}
+
+// CustomEnum sets an enum custom field to the option with the given gid.
+func CustomEnum(optionGID string) CustomFieldValue {
+	return CustomFieldValue{values: []string{optionGID}}
+}
+
+// CustomMultiEnum sets a multi_enum custom field to the options with the given gids.
+func CustomMultiEnum(optionGIDs ...string) CustomFieldValue {
+	return CustomFieldValue{values: optionGIDs}
+}
+
+func CustomDate(date Date) CustomFieldValue {
+	return CustomFieldValue{values: []string{date.String()}, subKey: "date"}
+}
+
+// CustomPeople sets a people custom field to the users with the given gids.
+func CustomPeople(userGIDs ...string) CustomFieldValue {
+	return CustomFieldValue{values: userGIDs}
+}
+
+// CustomFieldValues maps the gids of custom fields to the values to set them to.
+type CustomFieldValues map[string]CustomFieldValue
+
+// addTo form encodes the values into qs, in the form
+//
+//	custom_fields[<gid>]=<value>
+//
+// which is what Asana expects for create and update requests.
+func (cfv CustomFieldValues) addTo(qs url.Values) {
+	for gid, value := range cfv {
+		key := fmt.Sprintf("custom_fields[%s]", gid)
+		if value.subKey != "" {
+			key = fmt.Sprintf("%s[%s]", key, value.subKey)
+		}
+		qs.Set(key, strings.Join(value.values, ","))
+	}
+}
+
+type CustomFieldRequest struct {
+	// CustomFieldID is the gid of the custom field to update.
+	// It must be blank when creating a custom field.
+	CustomFieldID string `json:"-"`
+
+	// Workspace is the gid of the workspace to create the custom
+	// field in. It cannot be changed once the field is created.
+	Workspace string `json:"workspace,omitempty"`
+
+	Name        string          `json:"name,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Type        CustomFieldType `json:"resource_subtype,omitempty"`
+
+	// Precision is the number of decimal places for number fields.
+	Precision *int `json:"-"`
+
+	// EnumOptions are the initial options of enum and multi_enum
+	// fields. They are only used when creating the custom field.
+	EnumOptions []*EnumOptionRequest `json:"-"`
+}
+
+type EnumOptionRequest struct {
+	Name  string `json:"name,omitempty"`
+	Color string `json:"color,omitempty"`
+
+	// Enabled if set, enables or disables the option.
+	Enabled *bool `json:"-"`
+
+	// InsertBefore and InsertAfter are the gids of the
+	// options to place a new option before or after.
+	InsertBefore string `json:"insert_before,omitempty"`
+	InsertAfter  string `json:"insert_after,omitempty"`
+}
+
+var (
+	errNilCustomFieldRequest     = errors.New("expecting a non-nil customFieldRequest")
+	errEmptyCustomFieldID        = errors.New("expecting a non-empty customFieldID")
+	errEmptyCustomFieldName      = errors.New("expecting a non-empty custom field name")
+	errEmptyCustomFieldType      = errors.New("expecting a non-empty custom field type")
+	errImmutableCustomFieldType  = errors.New("the type of a custom field cannot be modified")
+	errNilEnumOptionRequest      = errors.New("expecting a non-nil enumOptionRequest")
+	errEmptyEnumOptionID         = errors.New("expecting a non-empty enumOptionID")
+	errEmptyEnumOptionName       = errors.New("expecting a non-empty enum option name")
+	errBothBeforeAndAfter        = errors.New("only one of insert before and insert after can be set")
+	errNilCustomFieldSettingReq  = errors.New("expecting a non-nil customFieldSettingRequest")
+	errNoEnumOptionsForFieldType = errors.New("enum options can only be set for enum and multi_enum fields")
+	errCustomFieldIDOnCreate     = errors.New("customFieldID must be blank when creating a custom field")
+)
+
+func (cfr *CustomFieldRequest) Validate() error {
+	if cfr == nil {
+		return errNilCustomFieldRequest
+	}
+	if strings.TrimSpace(cfr.Workspace) == "" {
+		return errEmptyWorkspace
+	}
+	if strings.TrimSpace(cfr.Name) == "" {
+		return errEmptyCustomFieldName
+	}
+	if cfr.Type == "" {
+		return errEmptyCustomFieldType
+	}
+	if len(cfr.EnumOptions) > 0 && cfr.Type != CustomFieldEnum && cfr.Type != CustomFieldMultiEnum {
+		return errNoEnumOptionsForFieldType
+	}
+	for _, eor := range cfr.EnumOptions {
+		if err := eor.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (eor *EnumOptionRequest) Validate() error {
+	if eor == nil {
+		return errNilEnumOptionRequest
+	}
+	if strings.TrimSpace(eor.Name) == "" {
+		return errEmptyEnumOptionName
+	}
+	if eor.InsertBefore != "" && eor.InsertAfter != "" {
+		return errBothBeforeAndAfter
+	}
+	return nil
+}
+
+func (cfr *CustomFieldRequest) toURLValues() (url.Values, error) {
+	qs, err := otils.ToURLValues(cfr)
+	if err != nil {
+		return nil, err
+	}
+	if cfr.Precision != nil {
+		qs.Set("precision", strconv.Itoa(*cfr.Precision))
+	}
+	for i, eor := range cfr.EnumOptions {
+		prefix := fmt.Sprintf("enum_options[%d]", i)
+		qs.Set(prefix+"[name]", eor.Name)
+		if eor.Color != "" {
+			qs.Set(prefix+"[color]", eor.Color)
+		}
+		if eor.Enabled != nil {
+			qs.Set(prefix+"[enabled]", strconv.FormatBool(*eor.Enabled))
+		}
+	}
+	return qs, nil
+}
+
+func (eor *EnumOptionRequest) toURLValues() (url.Values, error) {
+	qs, err := otils.ToURLValues(eor)
+	if err != nil {
+		return nil, err
+	}
+	if eor.Enabled != nil {
+		qs.Set("enabled", strconv.FormatBool(*eor.Enabled))
+	}
+	return qs, nil
+}
+
+type customFieldWrap struct {
+	CustomField *CustomField `json:"data"`
+}
+
+func parseOutCustomFieldFromData(blob []byte) (*CustomField, error) {
+	cfw := new(customFieldWrap)
+	if err := json.Unmarshal(blob, cfw); err != nil {
+		return nil, err
+	}
+	return cfw.CustomField, nil
+}
+
+type enumOptionWrap struct {
+	EnumOption *EnumOption `json:"data"`
+}
+
+func parseOutEnumOptionFromData(blob []byte) (*EnumOption, error) {
+	eow := new(enumOptionWrap)
+	if err := json.Unmarshal(blob, eow); err != nil {
+		return nil, err
+	}
+	return eow.EnumOption, nil
+}
+
+// CreateCustomField creates a custom field in a workspace. Enum and
+// multi_enum fields can be created with their initial EnumOptions.
+func (c *Client) CreateCustomField(cfr *CustomFieldRequest) (*CustomField, error) {
+	if err := cfr.Validate(); err != nil {
+		return nil, err
+	}
+	if cfr.CustomFieldID != "" {
+		return nil, errCustomFieldIDOnCreate
+	}
+	qs, err := cfr.toURLValues()
+	if err != nil {
+		return nil, err
+	}
+	slurp, err := c.doFormReq("POST", "/custom_fields", qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutCustomFieldFromData(slurp)
+}
+
+// UpdateCustomField changes the name, description or precision of
+// a custom field. The Workspace and Type of a custom field cannot be
+// changed once it has been created and trying to will return an error.
+// Use CreateEnumOption and UpdateEnumOption to manage its options.
+func (c *Client) UpdateCustomField(cfr *CustomFieldRequest) (*CustomField, error) {
+	if cfr == nil {
+		return nil, errNilCustomFieldRequest
+	}
+	customFieldID := strings.TrimSpace(cfr.CustomFieldID)
+	if customFieldID == "" {
+		return nil, errEmptyCustomFieldID
+	}
+	if cfr.Workspace != "" {
+		return nil, errImmutableWorkspace
+	}
+	if cfr.Type != "" {
+		return nil, errImmutableCustomFieldType
+	}
+
+	copyReq := *cfr
+	copyReq.EnumOptions = nil
+	qs, err := copyReq.toURLValues()
+	if err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/custom_fields/%s", customFieldID)
+	slurp, err := c.doFormReq("PUT", path, qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutCustomFieldFromData(slurp)
+}
+
+func (c *Client) FindCustomFieldByID(customFieldID string) (*CustomField, error) {
+	customFieldID = strings.TrimSpace(customFieldID)
+	if customFieldID == "" {
+		return nil, errEmptyCustomFieldID
+	}
+	fullURL := fmt.Sprintf("%s/custom_fields/%s", baseURL, customFieldID)
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutCustomFieldFromData(slurp)
+}
+
+func (c *Client) DeleteCustomField(customFieldID string) error {
+	customFieldID = strings.TrimSpace(customFieldID)
+	if customFieldID == "" {
+		return errEmptyCustomFieldID
+	}
+	fullURL := fmt.Sprintf("%s/custom_fields/%s", baseURL, customFieldID)
+	req, err := http.NewRequest("DELETE", fullURL, nil)
+	if err != nil {
+		return err
+	}
+	_, _, err = c.doAuthReqThenSlurpBody(req)
+	return err
+}
+
+// CreateEnumOption adds an option to an enum or multi_enum custom field.
+// It is placed at the end of the options unless one of InsertBefore
+// or InsertAfter is set.
+func (c *Client) CreateEnumOption(customFieldID string, eor *EnumOptionRequest) (*EnumOption, error) {
+	customFieldID = strings.TrimSpace(customFieldID)
+	if customFieldID == "" {
+		return nil, errEmptyCustomFieldID
+	}
+	if err := eor.Validate(); err != nil {
+		return nil, err
+	}
+	qs, err := eor.toURLValues()
+	if err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/custom_fields/%s/enum_options", customFieldID)
+	slurp, err := c.doFormReq("POST", path, qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutEnumOptionFromData(slurp)
+}
+
+// UpdateEnumOption changes the name, color or enabled state of an enum option.
+func (c *Client) UpdateEnumOption(enumOptionID string, eor *EnumOptionRequest) (*EnumOption, error) {
+	enumOptionID = strings.TrimSpace(enumOptionID)
+	if enumOptionID == "" {
+		return nil, errEmptyEnumOptionID
+	}
+	if eor == nil {
+		return nil, errNilEnumOptionRequest
+	}
+	copyReq := *eor
+	// Reordering is done through ReorderEnumOption.
+	copyReq.InsertBefore, copyReq.InsertAfter = "", ""
+	qs, err := copyReq.toURLValues()
+	if err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/enum_options/%s", enumOptionID)
+	slurp, err := c.doFormReq("PUT", path, qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutEnumOptionFromData(slurp)
+}
+
+// DisableEnumOption disables an enum option. Disabled options are kept on
+// the tasks that already have them but cannot be selected for other tasks.
+func (c *Client) DisableEnumOption(enumOptionID string) (*EnumOption, error) {
+	disabled := false
+	return c.UpdateEnumOption(enumOptionID, &EnumOptionRequest{Enabled: &disabled})
+}
+
+type EnumOptionReorder struct {
+	// EnumOptionID is the gid of the option to move.
+	EnumOptionID string `json:"enum_option"`
+
+	// Only one of BeforeEnumOptionID or AfterEnumOptionID can be set.
+	BeforeEnumOptionID string `json:"before_enum_option,omitempty"`
+	AfterEnumOptionID  string `json:"after_enum_option,omitempty"`
+}
+
+// ReorderEnumOption moves an option of an enum custom field
+// to before or after another one of its options.
+func (c *Client) ReorderEnumOption(customFieldID string, reorder *EnumOptionReorder) (*EnumOption, error) {
+	customFieldID = strings.TrimSpace(customFieldID)
+	if customFieldID == "" {
+		return nil, errEmptyCustomFieldID
+	}
+	if reorder == nil || strings.TrimSpace(reorder.EnumOptionID) == "" {
+		return nil, errEmptyEnumOptionID
+	}
+	if reorder.BeforeEnumOptionID != "" && reorder.AfterEnumOptionID != "" {
+		return nil, errBothBeforeAndAfter
+	}
+	qs, err := otils.ToURLValues(reorder)
+	if err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/custom_fields/%s/enum_options/insert", customFieldID)
+	slurp, err := c.doFormReq("POST", path, qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutEnumOptionFromData(slurp)
+}
+
+type CustomFieldsPage struct {
+	CustomFields []*CustomField `json:"data"`
+	Err          error
+}
+
+type customFieldsPager struct {
+	CustomFieldsPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+func (c *Client) ListCustomFieldsForWorkspace(workspaceID string) (pagesChan chan *CustomFieldsPage, cancelChan chan<- bool, err error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" {
+		return nil, nil, errEmptyWorkspace
+	}
+
+	pagesChan = make(chan *CustomFieldsPage)
+	path := fmt.Sprintf("/workspaces/%s/custom_fields", workspaceID)
+	cancel := c.sendPages(pagesChan, path, func(slurp []byte) (interface{}, string, error) {
+		pager := new(customFieldsPager)
+		if err := json.Unmarshal(slurp, pager); err != nil {
+			return nil, "", err
+		}
+		return &pager.CustomFieldsPage, pager.NextPage.nextPath(), nil
+	})
+	return pagesChan, cancel, nil
+}
+
+// CustomFieldSetting is the association of a
+// custom field with a project or a portfolio.
+type CustomFieldSetting struct {
+	ID  int64  `json:"id,omitempty"`
+	GID string `json:"gid,omitempty"`
+
+	CustomField *CustomField `json:"custom_field,omitempty"`
+
+	// Parent is the project or portfolio that the setting belongs to.
+	Parent *NamedAndIDdEntity `json:"parent,omitempty"`
+
+	// IsImportant is whether the custom field is
+	// shown on tasks in the list view of the parent.
+	IsImportant bool `json:"is_important,omitempty"`
+}
+
+type CustomFieldSettingRequest struct {
+	// CustomFieldID is the gid of the custom field to add.
+	CustomFieldID string `json:"custom_field"`
+
+	IsImportant bool `json:"is_important,omitempty"`
+
+	// InsertBefore and InsertAfter are the gids of the
+	// custom fields to place this custom field before or after.
+	InsertBefore string `json:"insert_before,omitempty"`
+	InsertAfter  string `json:"insert_after,omitempty"`
+}
+
+func (csr *CustomFieldSettingRequest) Validate() error {
+	if csr == nil {
+		return errNilCustomFieldSettingReq
+	}
+	if strings.TrimSpace(csr.CustomFieldID) == "" {
+		return errEmptyCustomFieldID
+	}
+	if csr.InsertBefore != "" && csr.InsertAfter != "" {
+		return errBothBeforeAndAfter
+	}
+	return nil
+}
+
+type customFieldSettingWrap struct {
+	CustomFieldSetting *CustomFieldSetting `json:"data"`
+}
+
+// AddCustomFieldSettingToProject adds a custom field to a project
+// so that the tasks in the project can have values for it.
+func (c *Client) AddCustomFieldSettingToProject(projectID string, csr *CustomFieldSettingRequest) (*CustomFieldSetting, error) {
+	projectID = strings.TrimSpace(projectID)
+	if projectID == "" {
+		return nil, errEmptyProjectID
+	}
+	return c.addCustomFieldSetting(fmt.Sprintf("/projects/%s/addCustomFieldSetting", projectID), csr)
+}
+
+// RemoveCustomFieldSettingFromProject removes a custom field from a project.
+func (c *Client) RemoveCustomFieldSettingFromProject(projectID, customFieldID string) error {
+	projectID = strings.TrimSpace(projectID)
+	if projectID == "" {
+		return errEmptyProjectID
+	}
+	return c.removeCustomFieldSetting(fmt.Sprintf("/projects/%s/removeCustomFieldSetting", projectID), customFieldID)
+}
+
+func (c *Client) addCustomFieldSetting(path string, csr *CustomFieldSettingRequest) (*CustomFieldSetting, error) {
+	if err := csr.Validate(); err != nil {
+		return nil, err
+	}
+	qs, err := otils.ToURLValues(csr)
+	if err != nil {
+		return nil, err
+	}
+	slurp, err := c.doFormReq("POST", path, qs)
+	if err != nil {
+		return nil, err
+	}
+	wrap := new(customFieldSettingWrap)
+	if err := json.Unmarshal(slurp, wrap); err != nil {
+		return nil, err
+	}
+	return wrap.CustomFieldSetting, nil
+}
+
+func (c *Client) removeCustomFieldSetting(path, customFieldID string) error {
+	customFieldID = strings.TrimSpace(customFieldID)
+	if customFieldID == "" {
+		return errEmptyCustomFieldID
+	}
+	qs := make(url.Values)
+	qs.Set("custom_field", customFieldID)
+	_, err := c.doFormReq("POST", path, qs)
+	return err
+}
+
+type CustomFieldSettingsPage struct {
+	CustomFieldSettings []*CustomFieldSetting `json:"data"`
+	Err                 error
+}
+
+type customFieldSettingsPager struct {
+	CustomFieldSettingsPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+func (c *Client) ListCustomFieldSettingsForProject(projectID string) (pagesChan chan *CustomFieldSettingsPage, cancelChan chan<- bool, err error) {
+	projectID = strings.TrimSpace(projectID)
+	if projectID == "" {
+		return nil, nil, errEmptyProjectID
+	}
+	return c.pageForCustomFieldSettings(fmt.Sprintf("/projects/%s/custom_field_settings", projectID))
+}
+
+func (c *Client) ListCustomFieldSettingsForPortfolio(portfolioID string) (pagesChan chan *CustomFieldSettingsPage, cancelChan chan<- bool, err error) {
+	portfolioID = strings.TrimSpace(portfolioID)
+	if portfolioID == "" {
+		return nil, nil, errEmptyPortfolioID
+	}
+	return c.pageForCustomFieldSettings(fmt.Sprintf("/portfolios/%s/custom_field_settings", portfolioID))
+}
+
+func (c *Client) pageForCustomFieldSettings(path string) (pagesChan chan *CustomFieldSettingsPage, cancelChan chan<- bool, err error) {
+	pagesChan = make(chan *CustomFieldSettingsPage)
+	cancel := c.sendPages(pagesChan, path, func(slurp []byte) (interface{}, string, error) {
+		pager := new(customFieldSettingsPager)
+		if err := json.Unmarshal(slurp, pager); err != nil {
+			return nil, "", err
+		}
+		return &pager.CustomFieldSettingsPage, pager.NextPage.nextPath(), nil
+	})
+	return pagesChan, cancel, nil
+}
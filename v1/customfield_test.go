@@ -0,0 +1,199 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestUpdateTaskWithCustomFields(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: updateTaskRoute})
+
+	task, err := client.UpdateTask("1001", &asana.TaskRequest{
+		CustomFields: asana.CustomFieldValues{
+			"201": asana.CustomEnum("2011"),
+			"202": asana.CustomNumber(3.5),
+			"203": asana.CustomText("OPS-42"),
+			"204": asana.CustomDate(asana.Date{Year: 2017, Month: time.March, Day: 5}),
+			"205": asana.CustomPeople("12345"),
+			"206": asana.CustomMultiEnum("2061", "2062"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("updating the task: %v", err)
+	}
+
+	priority := task.CustomFieldByName("Priority")
+	if enum, ok := priority.Enum(); !ok || enum.Name != "High" {
+		t.Errorf("Priority: got=%#v", enum)
+	}
+	if estimate, ok := task.CustomFieldByGID("202").Number(); !ok || estimate != 3.5 {
+		t.Errorf("Estimate: got=%v ok=%v", estimate, ok)
+	}
+	if ticket, ok := task.CustomFieldByName("Ticket").Text(); !ok || ticket != "OPS-42" {
+		t.Errorf("Ticket: got=%q ok=%v", ticket, ok)
+	}
+	if launch, ok := task.CustomFieldByName("Launch").Date(); !ok || launch.Date == nil || launch.Date.String() != "2017-03-05" {
+		t.Errorf("Launch: got=%#v", launch)
+	}
+	if reviewers, ok := task.CustomFieldByName("Reviewers").People(); !ok || len(reviewers) != 1 {
+		t.Errorf("Reviewers: got=%#v", reviewers)
+	}
+	if _, ok := task.CustomFieldByName("Platforms").MultiEnum(); ok {
+		t.Errorf("Platforms: expected no values")
+	}
+	if cf := task.CustomFieldByName("Nonexistent"); cf != nil {
+		t.Errorf("expected a nil custom field, got %#v", cf)
+	}
+	if _, ok := task.CustomFieldByName("Nonexistent").Text(); ok {
+		t.Errorf("expected no value from a nil custom field")
+	}
+}
+
+func (b *backend) updateTaskRoundTrip(req *http.Request) (*http.Response, error) {
+	if badAuthResp, err := b.checkAuthorization(req, "PUT"); err != nil || badAuthResp != nil {
+		return badAuthResp, err
+	}
+	if !strings.HasSuffix(req.URL.Path, "/tasks/1001") {
+		return makeResp("unexpected path: "+req.URL.Path, http.StatusNotFound, nil), nil
+	}
+	if err := req.ParseForm(); err != nil {
+		return makeResp(err.Error(), http.StatusBadRequest, nil), nil
+	}
+
+	wantForm := map[string]string{
+		"custom_fields[201]":       "2011",
+		"custom_fields[202]":       "3.5",
+		"custom_fields[203]":       "OPS-42",
+		"custom_fields[204][date]": "2017-03-05",
+		"custom_fields[205]":       "12345",
+		"custom_fields[206]":       "2061,2062",
+	}
+	for key, want := range wantForm {
+		if got := req.PostForm.Get(key); got != want {
+			return makeResp("unexpected value for "+key+": "+got, http.StatusBadRequest, nil), nil
+		}
+	}
+	return makeRespFromFile("./testdata/task-custom-fields-response.json")
+}
+
+func TestCreateCustomField(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: createCustomFieldRoute})
+
+	tests := [...]struct {
+		req       *asana.CustomFieldRequest
+		wantErr   bool
+		wantNames []string
+	}{
+		0: {
+			req: &asana.CustomFieldRequest{
+				Workspace:   "331783765164429",
+				Name:        "Priority",
+				Description: "How urgent the task is",
+				Type:        asana.CustomFieldEnum,
+				EnumOptions: []*asana.EnumOptionRequest{
+					{Name: "High", Color: "red"},
+					{Name: "Low", Color: "green"},
+				},
+			},
+			wantNames: []string{"High", "Low"},
+		},
+		1: {req: nil, wantErr: true},
+		2: {
+			// Missing the workspace.
+			req:     &asana.CustomFieldRequest{Name: "Priority", Type: asana.CustomFieldEnum},
+			wantErr: true,
+		},
+		3: {
+			// Enum options on a text field.
+			req: &asana.CustomFieldRequest{
+				Workspace:   "331783765164429",
+				Name:        "Ticket",
+				Type:        asana.CustomFieldText,
+				EnumOptions: []*asana.EnumOptionRequest{{Name: "High"}},
+			},
+			wantErr: true,
+		},
+		4: {
+			req: &asana.CustomFieldRequest{
+				Workspace:   "331783765164429",
+				Name:        "Priority",
+				Type:        asana.CustomFieldEnum,
+				EnumOptions: []*asana.EnumOptionRequest{{Name: "  "}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for i, tt := range tests {
+		cf, err := client.CreateCustomField(tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+
+		var gotNames []string
+		for _, option := range cf.EnumOptions {
+			gotNames = append(gotNames, option.Name)
+		}
+		if strings.Join(gotNames, ",") != strings.Join(tt.wantNames, ",") {
+			t.Errorf("#%d: gotNames=%v wantNames=%v", i, gotNames, tt.wantNames)
+		}
+	}
+}
+
+func (b *backend) createCustomFieldRoundTrip(req *http.Request) (*http.Response, error) {
+	if badAuthResp, err := b.checkAuthorization(req, "POST"); err != nil || badAuthResp != nil {
+		return badAuthResp, err
+	}
+	if err := req.ParseForm(); err != nil {
+		return makeResp(err.Error(), http.StatusBadRequest, nil), nil
+	}
+
+	wantForm := map[string]string{
+		"workspace":              "331783765164429",
+		"name":                   "Priority",
+		"resource_subtype":       "enum",
+		"enum_options[0][name]":  "High",
+		"enum_options[0][color]": "red",
+		"enum_options[1][name]":  "Low",
+		"enum_options[1][color]": "green",
+	}
+	for key, want := range wantForm {
+		if got := req.PostForm.Get(key); got != want {
+			return makeResp("unexpected value for "+key+": "+got, http.StatusBadRequest, nil), nil
+		}
+	}
+	return makeRespFromFile("./testdata/custom-field-response-201.json")
+}
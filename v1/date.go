@@ -0,0 +1,217 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Date is a civil date without a time or a location, such as
+// the values that Asana uses for "due_on" and "start_on".
+// Its zero value represents an unset date.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+const dateLayout = "2006-01-02"
+
+var (
+	_ json.Marshaler           = (*Date)(nil)
+	_ json.Unmarshaler         = (*Date)(nil)
+	_ encoding.TextMarshaler   = (*Date)(nil)
+	_ encoding.TextUnmarshaler = (*Date)(nil)
+)
+
+// ParseDate parses a date in the ISO 8601 form YYYY-MM-DD.
+// It returns an error for out of range values such as "2017-13-99".
+func ParseDate(str string) (Date, error) {
+	t, err := time.Parse(dateLayout, str)
+	if err != nil {
+		return Date{}, fmt.Errorf("expecting a date of the form YYYY-MM-DD: %v", err)
+	}
+	return DateOf(t), nil
+}
+
+// DateOf returns the date on which t occurs, in t's location.
+func DateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// Today returns the current date in the given location.
+// A nil location is treated as UTC.
+func Today(loc *time.Location) Date {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return DateOf(time.Now().In(loc))
+}
+
+// In returns the time at midnight at the start of d in loc.
+// A nil location is treated as UTC.
+func (d Date) In(loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// IsZero reports whether the date is unset.
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// IsValid reports whether d represents an actual day in the calendar.
+func (d Date) IsValid() bool {
+	return DateOf(d.In(time.UTC)) == d
+}
+
+// String returns the date in the zero padded YYYY-MM-DD form.
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+func (d Date) Weekday() time.Weekday {
+	return d.In(time.UTC).Weekday()
+}
+
+// AddDays returns the date n days after d.
+// n can be negative to go back in time.
+func (d Date) AddDays(n int) Date {
+	return DateOf(d.In(time.UTC).AddDate(0, 0, n))
+}
+
+func isWeekend(wd time.Weekday) bool {
+	return wd == time.Saturday || wd == time.Sunday
+}
+
+// AddBusinessDays returns the date n business days after d,
+// skipping Saturdays and Sundays. n can be negative.
+func (d Date) AddBusinessDays(n int) Date {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		d = d.AddDays(step)
+		if !isWeekend(d.Weekday()) {
+			n--
+		}
+	}
+	return d
+}
+
+// DaysSince returns the number of days from other until d.
+func (d Date) DaysSince(other Date) int {
+	// Counting from the civil dates rather than subtracting times
+	// keeps it exact even for dates that are centuries apart,
+	// beyond the range of a time.Duration.
+	return d.julianDay() - other.julianDay()
+}
+
+// julianDay returns the Julian day number of d
+// in the proleptic Gregorian calendar.
+func (d Date) julianDay() int {
+	// Normalize out of range values such as the 32nd of a month.
+	year, month, day := d.In(time.UTC).Date()
+	a := (14 - int(month)) / 12
+	y := year + 4800 - a
+	m := int(month) + 12*a - 3
+	return day + (153*m+2)/5 + 365*y + y/4 - y/100 + y/400 - 32045
+}
+
+func (d Date) Before(other Date) bool {
+	return d.Compare(other) < 0
+}
+
+func (d Date) After(other Date) bool {
+	return d.Compare(other) > 0
+}
+
+// Compare returns -1 if d is before other, +1 if
+// it is after other and 0 if both are the same date.
+func (d Date) Compare(other Date) int {
+	switch {
+	case d.Year != other.Year:
+		return compareInts(d.Year, other.Year)
+	case d.Month != other.Month:
+		return compareInts(int(d.Month), int(other.Month))
+	default:
+		return compareInts(d.Day, other.Day)
+	}
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (d Date) MarshalText() ([]byte, error) {
+	if d.IsZero() {
+		return []byte{}, nil
+	}
+	if !d.IsValid() {
+		return nil, fmt.Errorf("invalid date %q", d.String())
+	}
+	return []byte(d.String()), nil
+}
+
+func (d *Date) UnmarshalText(b []byte) error {
+	if len(b) == 0 {
+		*d = Date{}
+		return nil
+	}
+	parsed, err := ParseDate(string(b))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	text, err := d.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(strconv.Quote(string(text))), nil
+}
+
+func (d *Date) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*d = Date{}
+		return nil
+	}
+	unquoted, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return d.UnmarshalText([]byte(unquoted))
+}
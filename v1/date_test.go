@@ -0,0 +1,153 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := [...]struct {
+		in      string
+		want    asana.Date
+		wantErr bool
+	}{
+		0: {in: "2017-03-05", want: asana.Date{Year: 2017, Month: time.March, Day: 5}},
+		1: {in: "2016-02-29", want: asana.Date{Year: 2016, Month: time.February, Day: 29}},
+		2: {in: "2017-13-99", wantErr: true},
+		3: {in: "2017-02-29", wantErr: true},
+		4: {in: "2017-3-5", wantErr: true},
+		5: {in: "", wantErr: true},
+	}
+
+	for i, tt := range tests {
+		got, err := asana.ParseDate(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error, got %v", i, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("#%d: got=%v want=%v", i, got, tt.want)
+		}
+	}
+}
+
+func TestDateJSONRoundTrip(t *testing.T) {
+	type payload struct {
+		DueOn   *asana.Date `json:"due_on,omitempty"`
+		StartOn asana.Date  `json:"start_on"`
+	}
+
+	in := payload{
+		DueOn:   &asana.Date{Year: 2017, Month: time.March, Day: 5},
+		StartOn: asana.Date{},
+	}
+	blob, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+	if got, want := string(blob), `{"due_on":"2017-03-05","start_on":null}`; got != want {
+		t.Errorf("got=%s want=%s", got, want)
+	}
+
+	var out payload
+	if err := json.Unmarshal(blob, &out); err != nil {
+		t.Fatalf("unmarshaling: %v", err)
+	}
+	if out.DueOn == nil || *out.DueOn != *in.DueOn {
+		t.Errorf("DueOn: got=%v want=%v", out.DueOn, in.DueOn)
+	}
+	if !out.StartOn.IsZero() {
+		t.Errorf("StartOn: expected a zero date, got %v", out.StartOn)
+	}
+
+	if err := json.Unmarshal([]byte(`{"due_on":"2017-13-99"}`), &out); err == nil {
+		t.Errorf("expected an error for an invalid date")
+	}
+	if _, err := json.Marshal(asana.Date{Year: 2017, Month: time.February, Day: 30}); err == nil {
+		t.Errorf("expected an error marshaling an invalid date")
+	}
+}
+
+func TestDateArithmetic(t *testing.T) {
+	// 2017-03-03 is a Friday.
+	friday := asana.Date{Year: 2017, Month: time.March, Day: 3}
+
+	tests := [...]struct {
+		got, want asana.Date
+	}{
+		0: {got: friday.AddDays(1), want: asana.Date{Year: 2017, Month: time.March, Day: 4}},
+		1: {got: friday.AddDays(-3), want: asana.Date{Year: 2017, Month: time.February, Day: 28}},
+		2: {got: friday.AddBusinessDays(1), want: asana.Date{Year: 2017, Month: time.March, Day: 6}},
+		3: {got: friday.AddBusinessDays(6), want: asana.Date{Year: 2017, Month: time.March, Day: 13}},
+		4: {got: friday.AddBusinessDays(-5), want: asana.Date{Year: 2017, Month: time.February, Day: 24}},
+		5: {got: friday.AddBusinessDays(0), want: friday},
+	}
+
+	for i, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("#%d: got=%v want=%v", i, tt.got, tt.want)
+		}
+	}
+
+	later := friday.AddDays(10)
+	if !friday.Before(later) || !later.After(friday) || friday.Compare(friday) != 0 {
+		t.Errorf("unexpected ordering between %v and %v", friday, later)
+	}
+	if got, want := later.DaysSince(friday), 10; got != want {
+		t.Errorf("DaysSince: got=%d want=%d", got, want)
+	}
+
+	// Dates more than 292 years apart are beyond a time.Duration.
+	daysTests := [...]struct {
+		d, other asana.Date
+		want     int
+	}{
+		0: {d: friday, other: asana.Date{Year: 1, Month: time.January, Day: 1}, want: 736390},
+		1: {d: asana.Date{Year: 1600, Month: time.February, Day: 29}, other: asana.Date{Year: 2400, Month: time.December, Day: 31}, want: -292500},
+		2: {d: friday, other: friday, want: 0},
+	}
+	for i, tt := range daysTests {
+		if got := tt.d.DaysSince(tt.other); got != tt.want {
+			t.Errorf("DaysSince #%d: got=%d want=%d", i, got, tt.want)
+		}
+	}
+}
+
+func TestDateLocation(t *testing.T) {
+	loc := time.FixedZone("UTC-8", -8*60*60)
+	d := asana.Date{Year: 2017, Month: time.March, Day: 5}
+
+	start := d.In(loc)
+	if got, want := start.Format(time.RFC3339), "2017-03-05T00:00:00-08:00"; got != want {
+		t.Errorf("got=%s want=%s", got, want)
+	}
+
+	// 2017-03-06T02:00:00Z is still the 5th in UTC-8.
+	instant := time.Date(2017, time.March, 6, 2, 0, 0, 0, time.UTC)
+	if got := asana.DateOf(instant.In(loc)); got != d {
+		t.Errorf("got=%v want=%v", got, d)
+	}
+}
@@ -0,0 +1,338 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+type EventAction string
+
+const (
+	EventAdded     EventAction = "added"
+	EventRemoved   EventAction = "removed"
+	EventDeleted   EventAction = "deleted"
+	EventUndeleted EventAction = "undeleted"
+	EventChanged   EventAction = "changed"
+)
+
+// EventResource is a compact reference to the object that an event is about.
+type EventResource struct {
+	GID             string `json:"gid,omitempty"`
+	ResourceType    string `json:"resource_type,omitempty"`
+	ResourceSubtype string `json:"resource_subtype,omitempty"`
+	Name            string `json:"name,omitempty"`
+}
+
+// EventChange describes the field that a "changed" event is about.
+// The values are left undecoded since their type depends on Field.
+type EventChange struct {
+	Field        string          `json:"field,omitempty"`
+	Action       string          `json:"action,omitempty"`
+	NewValue     json.RawMessage `json:"new_value,omitempty"`
+	AddedValue   json.RawMessage `json:"added_value,omitempty"`
+	RemovedValue json.RawMessage `json:"removed_value,omitempty"`
+}
+
+// Event is a change to an object in Asana, as delivered
+// both to webhooks and through the events API.
+type Event struct {
+	User      *NamedAndIDdEntity `json:"user,omitempty"`
+	CreatedAt *time.Time         `json:"created_at,omitempty"`
+	Action    EventAction        `json:"action,omitempty"`
+
+	Resource *EventResource `json:"resource,omitempty"`
+
+	// Parent is the object that the resource was added
+	// to or removed from, for example the project of a task.
+	Parent *EventResource `json:"parent,omitempty"`
+
+	Change *EventChange `json:"change,omitempty"`
+}
+
+// ResourceType returns the type of the resource
+// that the event is about, such as "task" or "story".
+func (e *Event) ResourceType() string {
+	if e == nil || e.Resource == nil {
+		return ""
+	}
+	return e.Resource.ResourceType
+}
+
+// SyncTokenStore persists the latest sync token of each subscription
+// so that a restarted subscriber resumes where it left off instead
+// of missing the events that happened while it was down.
+type SyncTokenStore interface {
+	SaveSyncToken(resourceGID, token string) error
+
+	// SyncToken returns an empty token and a nil error
+	// if no token was saved for resourceGID.
+	SyncToken(resourceGID string) (string, error)
+}
+
+type memorySyncTokenStore struct {
+	sync.RWMutex
+	tokens map[string]string
+}
+
+// NewMemorySyncTokenStore returns a SyncTokenStore that keeps
+// tokens in memory. Tokens are lost when the process exits.
+func NewMemorySyncTokenStore() SyncTokenStore {
+	return &memorySyncTokenStore{tokens: make(map[string]string)}
+}
+
+func (msts *memorySyncTokenStore) SaveSyncToken(resourceGID, token string) error {
+	msts.Lock()
+	defer msts.Unlock()
+	msts.tokens[resourceGID] = token
+	return nil
+}
+
+func (msts *memorySyncTokenStore) SyncToken(resourceGID string) (string, error) {
+	msts.RLock()
+	defer msts.RUnlock()
+	return msts.tokens[resourceGID], nil
+}
+
+const defaultEventsPollInterval = 30 * time.Second
+
+type SubscribeOptions struct {
+	// Interval is the time to wait between polls once all
+	// the available events have been fetched. It defaults
+	// to 30 seconds.
+	Interval time.Duration
+
+	// Store if set, is used to load the sync token to resume from
+	// and to save the sync token of each page once it is acked.
+	// It defaults to an in-memory store.
+	Store SyncTokenStore
+}
+
+type EventsPage struct {
+	Events []*Event `json:"data"`
+
+	// SyncToken is the token to resume after this page from.
+	SyncToken string `json:"sync"`
+
+	// Expired is set if the previous sync token had expired.
+	// Any events since that token was issued were lost and
+	// consumers should fully resync the resource.
+	Expired bool `json:"-"`
+
+	Err error `json:"-"`
+
+	ack func() error
+}
+
+// Ack saves the sync token of the page, marking its events and those
+// of every earlier page as processed. Pages that are not acked are
+// delivered again when resuming from the SubscribeOptions.Store.
+func (ep *EventsPage) Ack() error {
+	if ep == nil || ep.ack == nil {
+		return nil
+	}
+	return ep.ack()
+}
+
+// syncTokenSaver saves the sync tokens of a subscription in the order
+// that their pages were delivered, never past an unacked page.
+type syncTokenSaver struct {
+	sync.Mutex
+
+	store       SyncTokenStore
+	resourceGID string
+
+	// delivered and saved are the sequence numbers of
+	// the latest delivered and the latest saved page.
+	delivered uint64
+	saved     uint64
+}
+
+// deliver assigns the next sequence number to page
+// and returns the func that acks it.
+func (sts *syncTokenSaver) deliver(syncToken string) func() error {
+	sts.Lock()
+	defer sts.Unlock()
+	sts.delivered++
+	seq := sts.delivered
+	return func() error {
+		sts.Lock()
+		defer sts.Unlock()
+		return sts.saveLocked(seq, syncToken)
+	}
+}
+
+// saveIfCaughtUp saves the sync token of a poll that returned no events
+// if every delivered page was acked, otherwise it would skip their events.
+func (sts *syncTokenSaver) saveIfCaughtUp(syncToken string) error {
+	sts.Lock()
+	defer sts.Unlock()
+	if sts.saved != sts.delivered {
+		return nil
+	}
+	sts.delivered++
+	return sts.saveLocked(sts.delivered, syncToken)
+}
+
+func (sts *syncTokenSaver) saveLocked(seq uint64, syncToken string) error {
+	if seq <= sts.saved {
+		return nil
+	}
+	if err := sts.store.SaveSyncToken(sts.resourceGID, syncToken); err != nil {
+		return err
+	}
+	sts.saved = seq
+	return nil
+}
+
+type eventsPager struct {
+	EventsPage
+
+	HasMore bool `json:"has_more,omitempty"`
+}
+
+var errEmptyResourceGID = errors.New("expecting a non-empty resourceGID")
+
+// Subscribe polls GET /events for changes to the resource with the given
+// gid and delivers them on pagesChan until cancelChan is signalled.
+// Fetching and transient errors are delivered as pages with Err set and
+// polling continues after the interval; it is up to the consumer to
+// cancel on errors that will not go away, such as an unauthorized token.
+//
+// The sync token of a page is only saved once the consumer calls its
+// Ack method after processing it, so that resuming from the store
+// delivers every event at least once.
+func (c *Client) Subscribe(resourceGID string, sopts *SubscribeOptions) (pagesChan chan *EventsPage, cancelChan chan<- bool, err error) {
+	resourceGID = strings.TrimSpace(resourceGID)
+	if resourceGID == "" {
+		return nil, nil, errEmptyResourceGID
+	}
+	if sopts == nil {
+		sopts = new(SubscribeOptions)
+	}
+	interval := sopts.Interval
+	if interval <= 0 {
+		interval = defaultEventsPollInterval
+	}
+	store := sopts.Store
+	if store == nil {
+		store = NewMemorySyncTokenStore()
+	}
+	syncToken, err := store.SyncToken(resourceGID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	saver := &syncTokenSaver{store: store, resourceGID: resourceGID}
+	cancel := make(chan bool, 1)
+	pagesChan = make(chan *EventsPage)
+
+	go func() {
+		defer close(pagesChan)
+
+		for {
+			pager, err := c.fetchEvents(resourceGID, syncToken)
+			if err != nil {
+				pager = &eventsPager{EventsPage: EventsPage{Err: err}}
+			}
+
+			page := pager.EventsPage
+			newToken := page.SyncToken != "" && page.SyncToken != syncToken
+			if len(page.Events) > 0 || page.Expired || page.Err != nil {
+				if newToken {
+					page.ack = saver.deliver(page.SyncToken)
+				}
+				select {
+				case pagesChan <- &page:
+				case <-cancel:
+					return
+				}
+			} else if newToken {
+				if err := saver.saveIfCaughtUp(page.SyncToken); err != nil {
+					select {
+					case pagesChan <- &EventsPage{Err: err}:
+					case <-cancel:
+						return
+					}
+				}
+			}
+			if newToken {
+				syncToken = page.SyncToken
+			}
+
+			if pager.HasMore && page.Err == nil {
+				select {
+				case <-cancel:
+					return
+				default:
+				}
+				continue
+			}
+
+			select {
+			case <-cancel:
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+
+	return pagesChan, cancel, nil
+}
+
+// fetchEvents makes a single request for the events since syncToken.
+// Asana responds with a 412 status code and a fresh sync token both
+// when syncToken is blank and when it has expired; only the latter
+// is reported as Expired.
+func (c *Client) fetchEvents(resourceGID, syncToken string) (*eventsPager, error) {
+	qs := make(url.Values)
+	qs.Set("resource", resourceGID)
+	if syncToken != "" {
+		qs.Set("sync", syncToken)
+	}
+	fullURL := fmt.Sprintf("%s/events?%s", baseURL, qs.Encode())
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	pager := new(eventsPager)
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		he, ok := err.(*HTTPError)
+		if !ok || he.Code() != http.StatusPreconditionFailed {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(he.msg), pager); err != nil {
+			return nil, err
+		}
+		// The events of a 412 response are never meaningful.
+		pager.Events = nil
+		pager.Expired = syncToken != ""
+		return pager, nil
+	}
+
+	if err := json.Unmarshal(slurp, pager); err != nil {
+		return nil, err
+	}
+	return pager, nil
+}
@@ -0,0 +1,174 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestSubscribe(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: eventsRoute})
+
+	tests := [...]struct {
+		resourceGID string
+		savedToken  string
+		noAck       bool
+		wantErr     bool
+
+		wantExpired   bool
+		wantEvents    []string
+		wantSyncToken string
+	}{
+		0: {resourceGID: "", wantErr: true},
+		1: {resourceGID: "   ", wantErr: true},
+
+		// A fresh subscription first gets a sync
+		// token without that being reported as expired.
+		2: {
+			resourceGID:   projectID1,
+			wantEvents:    []string{"1001", "1002", "1003"},
+			wantSyncToken: "sync-3",
+		},
+
+		// Resuming from a saved token.
+		3: {
+			resourceGID:   projectID1,
+			savedToken:    "sync-2",
+			wantEvents:    []string{"1003"},
+			wantSyncToken: "sync-3",
+		},
+
+		// An expired token is reset.
+		4: {
+			resourceGID:   projectID1,
+			savedToken:    "too-old",
+			wantExpired:   true,
+			wantEvents:    []string{"1001", "1002", "1003"},
+			wantSyncToken: "sync-3",
+		},
+
+		// Pages that were not acked are not saved
+		// so that they are delivered again.
+		5: {
+			resourceGID:   projectID1,
+			noAck:         true,
+			wantEvents:    []string{"1001", "1002", "1003"},
+			wantSyncToken: "sync-1",
+		},
+	}
+
+	for i, tt := range tests {
+		store := asana.NewMemorySyncTokenStore()
+		if tt.savedToken != "" {
+			store.SaveSyncToken(tt.resourceGID, tt.savedToken)
+		}
+		pagesChan, cancelChan, err := client.Subscribe(tt.resourceGID, &asana.SubscribeOptions{
+			Interval: time.Millisecond,
+			Store:    store,
+		})
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: expected a non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected err: %v", i, err)
+			continue
+		}
+
+		var gotEvents []string
+		gotExpired := false
+		timeout := time.After(5 * time.Second)
+	receive:
+		for len(gotEvents) < len(tt.wantEvents) {
+			select {
+			case page := <-pagesChan:
+				if page.Err != nil {
+					t.Errorf("#%d: page err: %v", i, page.Err)
+					break receive
+				}
+				gotExpired = gotExpired || page.Expired
+				for _, event := range page.Events {
+					gotEvents = append(gotEvents, event.Resource.GID)
+				}
+				if !tt.noAck {
+					if err := page.Ack(); err != nil {
+						t.Errorf("#%d: ack err: %v", i, err)
+					}
+				}
+			case <-timeout:
+				t.Errorf("#%d: timed out waiting for events", i)
+				break receive
+			}
+		}
+
+		cancelChan <- true
+		for range pagesChan {
+		}
+
+		if !reflect.DeepEqual(gotEvents, tt.wantEvents) {
+			t.Errorf("#%d:\ngotEvents:  %v\nwantEvents: %v", i, gotEvents, tt.wantEvents)
+		}
+		if gotExpired != tt.wantExpired {
+			t.Errorf("#%d: gotExpired=%v wantExpired=%v", i, gotExpired, tt.wantExpired)
+		}
+		gotToken, _ := store.SyncToken(tt.resourceGID)
+		if gotToken != tt.wantSyncToken {
+			t.Errorf("#%d: gotSyncToken=%q wantSyncToken=%q", i, gotToken, tt.wantSyncToken)
+		}
+	}
+}
+
+// eventsRoundTrip serves the events of projectID1 as a chain of
+// sync tokens: "sync-1" has more events right away, "sync-2" has
+// one more event and "sync-3" is the latest, with nothing new.
+func (b *backend) eventsRoundTrip(req *http.Request) (*http.Response, error) {
+	if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
+		return badAuthResp, err
+	}
+	query := req.URL.Query()
+	if got := query.Get("resource"); got != projectID1 {
+		return makeResp("unknown resource: "+got, http.StatusForbidden, nil), nil
+	}
+
+	switch query.Get("sync") {
+	case "":
+		body := `{"errors": [{"message": "Sync token invalid or too old."}], "sync": "sync-1"}`
+		return makeResp("412 Precondition Failed", http.StatusPreconditionFailed, nopCloser(body)), nil
+	case "sync-1":
+		body := `{"data": [{"action": "added", "resource": {"gid": "1001", "resource_type": "task"}},
+				{"action": "changed", "resource": {"gid": "1002", "resource_type": "task"}}],
+			"sync": "sync-2", "has_more": true}`
+		return makeResp("200 OK", http.StatusOK, nopCloser(body)), nil
+	case "sync-2":
+		body := `{"data": [{"action": "deleted", "resource": {"gid": "1003", "resource_type": "task"}}], "sync": "sync-3"}`
+		return makeResp("200 OK", http.StatusOK, nopCloser(body)), nil
+	case "sync-3":
+		return makeResp("200 OK", http.StatusOK, nopCloser(`{"data": [], "sync": "sync-3"}`)), nil
+	default:
+		body := `{"errors": [{"message": "Sync token invalid or too old."}], "sync": "sync-1"}`
+		return makeResp("412 Precondition Failed", http.StatusPreconditionFailed, nopCloser(body)), nil
+	}
+}
@@ -16,8 +16,11 @@ package asana_test
 
 import (
 	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/orijtech/asana/v1"
 )
@@ -132,6 +135,29 @@ func Example_client_FindTaskByID() {
 	}
 }
 
+func Example_client_UpdateTask() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	task, err := client.UpdateTask("332508471165497", &asana.TaskRequest{
+		CustomFields: asana.CustomFieldValues{
+			// The gid of the "Priority" enum field and that of its "High" option.
+			"332508471165500": asana.CustomEnum("332508471165501"),
+			// The gid of the "Estimate" number field.
+			"332508471165510": asana.CustomNumber(3.5),
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if priority, ok := task.CustomFieldByName("Priority").Enum(); ok {
+		log.Printf("Priority: %s", priority.Name)
+	}
+}
+
 func Example_client_DeleteTask() {
 	client, err := asana.NewClient()
 	if err != nil {
@@ -172,6 +198,38 @@ func Example_client_ListTasksForProject() {
 	}
 }
 
+func Example_client_QueryForTasks() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	lastSync := time.Now().Add(-24 * time.Hour)
+	taskPagesChan, _, err := client.QueryForTasks(&asana.TaskQuery{
+		Assignee:       asana.MeAsUser,
+		Workspace:      "331783765164429",
+		ModifiedSince:  &lastSync,
+		IncompleteOnly: true,
+		OptFields:      []string{"name", "modified_at"},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pageCount := 0
+	for page := range taskPagesChan {
+		if err := page.Err; err != nil {
+			log.Printf("Page: #%d err: %v", pageCount, err)
+			continue
+		}
+
+		for i, task := range page.Tasks {
+			log.Printf("Page: #%d i: %d task: %#v", pageCount, i, task)
+		}
+		pageCount += 1
+	}
+}
+
 func Example_client_CreateProject() {
 	client, err := asana.NewClient()
 	if err != nil {
@@ -476,3 +534,435 @@ func Example_client_ListAllAttachmentsForTask() {
 		fmt.Printf("Task #%d: %#v\n\n", i, task)
 	}
 }
+
+func Example_client_CreateCustomField() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	priority, err := client.CreateCustomField(&asana.CustomFieldRequest{
+		Workspace: "331783765164429",
+		Name:      "Priority",
+		Type:      asana.CustomFieldEnum,
+		EnumOptions: []*asana.EnumOptionRequest{
+			{Name: "High", Color: "red"},
+			{Name: "Low", Color: "green"},
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	setting, err := client.AddCustomFieldSettingToProject("332697649493087", &asana.CustomFieldSettingRequest{
+		CustomFieldID: priority.GID,
+		IsImportant:   true,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Added custom field setting: %#v", setting)
+}
+
+func Example_client_ListCustomFieldsForWorkspace() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pagesChan, _, err := client.ListCustomFieldsForWorkspace("331783765164429")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pageCount := 0
+	for page := range pagesChan {
+		if err := page.Err; err != nil {
+			log.Printf("Page: #%d err: %v", pageCount, err)
+			continue
+		}
+
+		for i, customField := range page.CustomFields {
+			log.Printf("Page: #%d i: %d customField: %#v", pageCount, i, customField)
+		}
+		pageCount += 1
+	}
+}
+
+func Example_client_AddTaskToSection() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pagesChan, _, err := client.ListSectionsForProject("332697649493087")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var deployed *asana.Section
+	for page := range pagesChan {
+		if err := page.Err; err != nil {
+			log.Fatal(err)
+		}
+		for _, section := range page.Sections {
+			if section.Name == "Deployed" {
+				deployed = section
+			}
+		}
+	}
+	if deployed == nil {
+		log.Fatal("no \"Deployed\" section in the project")
+	}
+
+	err = client.AddTaskToSection(deployed.GID, &asana.SectionTaskInsert{
+		TaskID: "332508471165497",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func Example_client_AddComment() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	comment, err := client.AddComment("332508471165497", &asana.StoryRequest{
+		Text: "Deployed v1.2 to staging",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Posted comment: %#v", comment)
+}
+
+func Example_client_ListStoriesForTask() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pagesChan, _, err := client.ListStoriesForTask("332508471165497")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pageCount := 0
+	for page := range pagesChan {
+		if err := page.Err; err != nil {
+			log.Printf("Page: #%d err: %v", pageCount, err)
+			continue
+		}
+
+		for i, comment := range asana.FilterComments(page.Stories) {
+			log.Printf("Page: #%d i: %d %s said: %s", pageCount, i, comment.CreatedBy.Name, comment.Text)
+		}
+		pageCount += 1
+	}
+}
+
+func Example_client_FindOrCreateTag() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	tag, err := client.FindOrCreateTag("331783765164429", "needs-triage")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := client.AddTagToTask("332508471165497", tag.GID); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Tagged the task with %q", tag.Name)
+}
+
+func Example_client_CreateWebhook() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	handler := &asana.WebhookHandler{Secrets: asana.NewMemorySecretStore()}
+	handler.Handle("task", asana.EventChanged, func(event *asana.Event) error {
+		log.Printf("Task %s changed its %q", event.Resource.GID, event.Change.Field)
+		return nil
+	})
+	http.Handle("/hooks/project", handler)
+
+	// The target must be serving to complete
+	// the handshake before CreateWebhook returns.
+	go func() {
+		log.Fatal(http.ListenAndServeTLS(":8443", "cert.pem", "key.pem", nil))
+	}()
+
+	webhook, err := client.CreateWebhook(&asana.WebhookRequest{
+		Resource: "332508471165497",
+		Target:   "https://hooks.example.com:8443/hooks/project",
+		Filters: []*asana.WebhookFilter{
+			{ResourceType: "task", Action: asana.EventChanged},
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Webhook: %#v\n", webhook)
+}
+
+func Example_client_Subscribe() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pagesChan, cancelChan, err := client.Subscribe("332508471165497", &asana.SubscribeOptions{
+		Interval: 10 * time.Second,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	stop := time.After(10 * time.Minute)
+	for {
+		select {
+		case page := <-pagesChan:
+			if page.Err != nil {
+				log.Printf("Polling err: %v", page.Err)
+				continue
+			}
+			if page.Expired {
+				log.Printf("Missed some events, resyncing the project")
+			}
+			for _, event := range page.Events {
+				log.Printf("%s %s was %s", event.ResourceType(), event.Resource.GID, event.Action)
+			}
+			// Only resume after these events once they were processed.
+			if err := page.Ack(); err != nil {
+				log.Printf("Saving the sync token: %v", err)
+			}
+		case <-stop:
+			cancelChan <- true
+			return
+		}
+	}
+}
+
+func Example_client_CreateStatusUpdate() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	su, err := client.CreateStatusUpdate(&asana.StatusUpdateRequest{
+		Parent:     "332508471165497",
+		StatusType: asana.StatusAtRisk,
+		Title:      "Week 34",
+		HTMLText:   "<body>The vendor API is <strong>late</strong>.</body>",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Posted status update: %s", su.GID)
+}
+
+func Example_client_DuplicateProject() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	job, err := client.DuplicateProject("332508471165497", &asana.ProjectDuplicateRequest{
+		Name: "Sprint 42",
+		Include: []asana.DuplicateOption{
+			asana.DuplicateMembers, asana.DuplicateTaskSubtasks,
+			asana.DuplicateTaskAssignee, asana.DuplicateTaskDates,
+		},
+		ScheduleDates: &asana.ScheduleDates{
+			StartOn:      &asana.Date{Year: 2017, Month: time.October, Day: 2},
+			SkipWeekends: true,
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	job, err = client.WaitForJob(job.GID, &asana.WaitOptions{Timeout: 2 * time.Minute})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("The new project is: %s", job.NewProject.GID)
+}
+
+func Example_client_AddMembersToProject() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	project, err := client.AddMembersToProject("332508471165497", "7", "8")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for i, member := range project.Members {
+		log.Printf("Member #%d: %s", i, member.Name)
+	}
+}
+
+func Example_client_Me() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	me, err := client.Me()
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Signed in as %s <%s>", me.Name, me.Email)
+	for _, workspace := range me.Workspaces {
+		log.Printf("Member of: %s", workspace.Name)
+	}
+}
+
+func Example_client_TasksForUserTaskList() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	myTasks, err := client.FindUserTaskListForUser("me", "331783765164429")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	resultsChan, _, err := client.TasksForUserTaskList(myTasks.GID, &asana.UserTaskListQuery{
+		IncompleteOnly: true,
+		OptFields:      []string{"name", "assignee_section.name"},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for page := range resultsChan {
+		if err := page.Err; err != nil {
+			log.Printf("Page err: %v", err)
+			continue
+		}
+		for _, task := range page.Tasks {
+			log.Printf("%s is in %q", task.Name, task.AssigneeSection.Name)
+		}
+	}
+}
+
+func Example_client_ItemsForPortfolio() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pagesChan, _, err := client.ItemsForPortfolio("332508471165498")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for page := range pagesChan {
+		if err := page.Err; err != nil {
+			log.Printf("Page err: %v", err)
+			continue
+		}
+		for _, project := range page.Projects {
+			log.Printf("Project: %s", project.Name)
+		}
+	}
+}
+
+func Example_client_SetGoalMetricCurrentValue() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	goal, err := client.SetGoalMetricCurrentValue("1201540927012377", 42)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("%q is %.0f%% of the way there", goal.Name, 100*goal.Metric.Progress())
+}
+
+func Example_client_SummarizeTimeTracking() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	since := asana.Date{Year: 2017, Month: time.September, Day: 1}
+	until := asana.Date{Year: 2017, Month: time.September, Day: 30}
+	summary, err := client.SummarizeTimeTracking(&asana.TimeTrackingQuery{
+		ProjectID: "332508471165498",
+		Since:     &since,
+		Until:     &until,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Tracked %v in September", summary.Total())
+	for userID, minutes := range summary.ByUser {
+		log.Printf("User %s: %.1f hours", userID, float64(minutes)/60)
+	}
+}
+
+func Example_client_DownloadAttachment() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	rc, attachment, err := client.DownloadAttachment("331783765164429")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rc.Close()
+
+	f, err := os.Create(string(attachment.Name))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	n, err := io.Copy(f, rc)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Saved %d bytes of %s", n, rc.(*asana.AttachmentContent).ContentType)
+}
+
+func Example_client_CreateExternalAttachment() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	attachment, err := client.CreateExternalAttachment(&asana.ExternalAttachmentRequest{
+		ParentID: "331783765164429",
+		URL:      "https://ci.example.com/builds/1024/artifacts",
+		Name:     "Build #1024 artifacts",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Linked: %s", attachment.ViewURL)
+}
+
+func Example_client_UploadAttachmentFromFile() {
+	client, err := asana.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	attachment, err := client.UploadAttachmentFromFile("331783765164429", "./designs/homepage.pdf")
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Uploaded: %s", attachment.ViewURL)
+}
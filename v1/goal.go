@@ -0,0 +1,553 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/orijtech/otils"
+)
+
+type Goal struct {
+	ID        int64  `json:"id,omitempty"`
+	GID       string `json:"gid,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Notes     string `json:"notes,omitempty"`
+	HTMLNotes string `json:"html_notes,omitempty"`
+
+	StartOn *Date `json:"start_on,omitempty"`
+	DueOn   *Date `json:"due_on,omitempty"`
+
+	// IsWorkspaceLevel is set for goals that belong
+	// to the workspace rather than to a team.
+	IsWorkspaceLevel bool `json:"is_workspace_level,omitempty"`
+
+	Owner      *NamedAndIDdEntity `json:"owner,omitempty"`
+	Team       *NamedAndIDdEntity `json:"team,omitempty"`
+	Workspace  *NamedAndIDdEntity `json:"workspace,omitempty"`
+	TimePeriod *TimePeriod        `json:"time_period,omitempty"`
+
+	Metric *GoalMetric `json:"metric,omitempty"`
+
+	// Status is the color of the latest status
+	// update, such as "green", "yellow" or "red".
+	Status              string        `json:"status,omitempty"`
+	CurrentStatusUpdate *StatusUpdate `json:"current_status_update,omitempty"`
+
+	Followers []*NamedAndIDdEntity `json:"followers,omitempty"`
+}
+
+// TimePeriod is a period, such as a quarter, that goals are set for.
+type TimePeriod struct {
+	GID string `json:"gid,omitempty"`
+
+	// DisplayName is for example "Q1 FY22".
+	DisplayName string `json:"display_name,omitempty"`
+
+	// Period is one of "FY", "H1", "H2", "Q1", "Q2", "Q3" or "Q4".
+	Period string `json:"period,omitempty"`
+
+	StartOn *Date `json:"start_on,omitempty"`
+	EndOn   *Date `json:"end_on,omitempty"`
+}
+
+type MetricUnit string
+
+const (
+	MetricNone       MetricUnit = "none"
+	MetricCurrency   MetricUnit = "currency"
+	MetricPercentage MetricUnit = "percentage"
+)
+
+// GoalMetric is the number that measures the progress of a goal.
+type GoalMetric struct {
+	GID string `json:"gid,omitempty"`
+
+	Unit MetricUnit `json:"unit,omitempty"`
+
+	// CurrencyCode is the ISO 4217 code of the currency
+	// for metrics whose Unit is MetricCurrency.
+	CurrencyCode string `json:"currency_code,omitempty"`
+
+	// Precision is the number of decimal places shown.
+	Precision int `json:"precision,omitempty"`
+
+	InitialValue float64 `json:"initial_number_value"`
+	TargetValue  float64 `json:"target_number_value"`
+	CurrentValue float64 `json:"current_number_value"`
+
+	CurrentDisplayValue string `json:"current_display_value,omitempty"`
+
+	// ProgressSource is how the current value is updated,
+	// for example "manual" or "subgoal_progress".
+	ProgressSource string `json:"progress_source,omitempty"`
+}
+
+// Progress returns the fraction of the way from the
+// initial to the target value that the metric is at.
+func (gm *GoalMetric) Progress() float64 {
+	if gm == nil || gm.TargetValue == gm.InitialValue {
+		return 0
+	}
+	return (gm.CurrentValue - gm.InitialValue) / (gm.TargetValue - gm.InitialValue)
+}
+
+type GoalRequest struct {
+	// GoalID is the gid of the goal to update.
+	GoalID string `json:"-"`
+
+	// Workspace is the gid of the workspace to create the goal
+	// in. It cannot be changed once the goal has been created.
+	Workspace string `json:"workspace,omitempty"`
+
+	Name      string `json:"name,omitempty"`
+	Notes     string `json:"notes,omitempty"`
+	HTMLNotes string `json:"html_notes,omitempty"`
+
+	// Team is the gid of the team that the goal belongs to.
+	// Leave it blank for workspace level goals.
+	Team string `json:"team,omitempty"`
+
+	// TimePeriod is the gid of the time period of the goal.
+	TimePeriod string `json:"time_period,omitempty"`
+
+	// Owner is the gid of the user who owns the goal.
+	Owner string `json:"owner,omitempty"`
+
+	StartOn *Date `json:"start_on,omitempty"`
+	DueOn   *Date `json:"due_on,omitempty"`
+
+	IsWorkspaceLevel *bool `json:"-"`
+}
+
+var (
+	errNilGoalRequest   = errors.New("expecting a non-nil goalRequest")
+	errEmptyGoalID      = errors.New("expecting a non-empty goalID")
+	errEmptyGoalName    = errors.New("expecting a non-empty goal name")
+	errNilGoalMetricReq = errors.New("expecting a non-nil goalMetricRequest")
+	errEmptyMetricUnit  = errors.New("expecting a non-empty metric unit")
+	errEmptyCurrency    = errors.New("expecting a currency code for a currency metric")
+	errNilGoalQuery     = errors.New("expecting a non-nil goalQuery")
+	errGoalQueryFilter  = errors.New("expecting one of workspace, team, portfolio or project to be set")
+	errNilGoalSupport   = errors.New("expecting a non-nil goalSupportRequest")
+	errEmptyResourceID  = errors.New("expecting a non-empty resourceID")
+)
+
+func (greq *GoalRequest) Validate() error {
+	if greq == nil {
+		return errNilGoalRequest
+	}
+	if strings.TrimSpace(greq.Workspace) == "" {
+		return errEmptyWorkspace
+	}
+	if strings.TrimSpace(greq.Name) == "" {
+		return errEmptyGoalName
+	}
+	return greq.validate()
+}
+
+func (greq *GoalRequest) validate() error {
+	if greq.StartOn != nil && greq.DueOn != nil && greq.StartOn.After(*greq.DueOn) {
+		return errStartAfterDue
+	}
+	return validateRichText(greq.HTMLNotes)
+}
+
+func (greq *GoalRequest) toURLValues() (url.Values, error) {
+	qs, err := otils.ToURLValues(greq)
+	if err != nil {
+		return nil, err
+	}
+	if greq.IsWorkspaceLevel != nil {
+		qs.Set("is_workspace_level", strconv.FormatBool(*greq.IsWorkspaceLevel))
+	}
+	return qs, nil
+}
+
+type goalWrap struct {
+	Goal *Goal `json:"data"`
+}
+
+func parseOutGoalFromData(blob []byte) (*Goal, error) {
+	gw := new(goalWrap)
+	if err := json.Unmarshal(blob, gw); err != nil {
+		return nil, err
+	}
+	return gw.Goal, nil
+}
+
+func (c *Client) CreateGoal(greq *GoalRequest) (*Goal, error) {
+	if err := greq.Validate(); err != nil {
+		return nil, err
+	}
+	qs, err := greq.toURLValues()
+	if err != nil {
+		return nil, err
+	}
+	slurp, err := c.doFormReq("POST", "/goals", qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutGoalFromData(slurp)
+}
+
+// UpdateGoal changes the attributes of a goal. The Workspace
+// of a goal cannot be changed once it has been created and
+// trying to will return an error.
+func (c *Client) UpdateGoal(greq *GoalRequest) (*Goal, error) {
+	if greq == nil {
+		return nil, errNilGoalRequest
+	}
+	goalID := strings.TrimSpace(greq.GoalID)
+	if goalID == "" {
+		return nil, errEmptyGoalID
+	}
+	if greq.Workspace != "" {
+		return nil, errImmutableWorkspace
+	}
+	if err := greq.validate(); err != nil {
+		return nil, err
+	}
+	qs, err := greq.toURLValues()
+	if err != nil {
+		return nil, err
+	}
+	slurp, err := c.doFormReq("PUT", fmt.Sprintf("/goals/%s", goalID), qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutGoalFromData(slurp)
+}
+
+func (c *Client) FindGoalByID(goalID string) (*Goal, error) {
+	goalID = strings.TrimSpace(goalID)
+	if goalID == "" {
+		return nil, errEmptyGoalID
+	}
+	fullURL := fmt.Sprintf("%s/goals/%s", baseURL, goalID)
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutGoalFromData(slurp)
+}
+
+func (c *Client) DeleteGoal(goalID string) error {
+	goalID = strings.TrimSpace(goalID)
+	if goalID == "" {
+		return errEmptyGoalID
+	}
+	fullURL := fmt.Sprintf("%s/goals/%s", baseURL, goalID)
+	req, err := http.NewRequest("DELETE", fullURL, nil)
+	if err != nil {
+		return err
+	}
+	_, _, err = c.doAuthReqThenSlurpBody(req)
+	return err
+}
+
+// GoalMetricRequest defines the metric that measures a goal.
+type GoalMetricRequest struct {
+	Unit         MetricUnit `json:"unit"`
+	CurrencyCode string     `json:"currency_code,omitempty"`
+	Precision    int        `json:"precision,omitempty"`
+
+	InitialValue float64 `json:"initial_number_value"`
+	TargetValue  float64 `json:"target_number_value"`
+
+	ProgressSource string `json:"progress_source,omitempty"`
+}
+
+func (gmr *GoalMetricRequest) Validate() error {
+	if gmr == nil {
+		return errNilGoalMetricReq
+	}
+	if strings.TrimSpace(string(gmr.Unit)) == "" {
+		return errEmptyMetricUnit
+	}
+	if gmr.Unit == MetricCurrency && strings.TrimSpace(gmr.CurrencyCode) == "" {
+		return errEmptyCurrency
+	}
+	return nil
+}
+
+// SetGoalMetric defines or replaces the metric of a goal.
+func (c *Client) SetGoalMetric(goalID string, gmr *GoalMetricRequest) (*Goal, error) {
+	goalID = strings.TrimSpace(goalID)
+	if goalID == "" {
+		return nil, errEmptyGoalID
+	}
+	if err := gmr.Validate(); err != nil {
+		return nil, err
+	}
+	qs, err := otils.ToURLValues(gmr)
+	if err != nil {
+		return nil, err
+	}
+	// Zero values are dropped when encoding yet
+	// a metric commonly starts from zero.
+	qs.Set("initial_number_value", strconv.FormatFloat(gmr.InitialValue, 'f', -1, 64))
+	qs.Set("target_number_value", strconv.FormatFloat(gmr.TargetValue, 'f', -1, 64))
+	slurp, err := c.doFormReq("POST", fmt.Sprintf("/goals/%s/setMetric", goalID), qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutGoalFromData(slurp)
+}
+
+// SetGoalMetricCurrentValue records the latest value of the metric
+// of a goal, for example from a metrics pipeline.
+func (c *Client) SetGoalMetricCurrentValue(goalID string, value float64) (*Goal, error) {
+	goalID = strings.TrimSpace(goalID)
+	if goalID == "" {
+		return nil, errEmptyGoalID
+	}
+	qs := make(url.Values)
+	qs.Set("current_number_value", strconv.FormatFloat(value, 'f', -1, 64))
+	slurp, err := c.doFormReq("POST", fmt.Sprintf("/goals/%s/setMetricCurrentValue", goalID), qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutGoalFromData(slurp)
+}
+
+func (c *Client) AddFollowersToGoal(goalID string, userIDs ...string) (*Goal, error) {
+	return c.changeGoalFollowers("addFollowers", goalID, userIDs)
+}
+
+func (c *Client) RemoveFollowersFromGoal(goalID string, userIDs ...string) (*Goal, error) {
+	return c.changeGoalFollowers("removeFollowers", goalID, userIDs)
+}
+
+func (c *Client) changeGoalFollowers(action, goalID string, userIDs []string) (*Goal, error) {
+	goalID = strings.TrimSpace(goalID)
+	if goalID == "" {
+		return nil, errEmptyGoalID
+	}
+	followers := joinUserIDs(userIDs)
+	if followers == "" {
+		return nil, errEmptyUserIDs
+	}
+	qs := make(url.Values)
+	qs.Set("followers", followers)
+	slurp, err := c.doFormReq("POST", fmt.Sprintf("/goals/%s/%s", goalID, action), qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutGoalFromData(slurp)
+}
+
+// GoalRelationship links a goal to a subgoal,
+// project, task or portfolio that supports it.
+type GoalRelationship struct {
+	GID string `json:"gid,omitempty"`
+
+	// Subtype is the kind of the supporting resource, for
+	// example "subgoal", "supporting_project" or "supporting_task".
+	Subtype string `json:"resource_subtype,omitempty"`
+
+	SupportedGoal      *NamedAndIDdEntity `json:"supported_goal,omitempty"`
+	SupportingResource *NamedAndIDdEntity `json:"supporting_resource,omitempty"`
+
+	// ContributionWeight is how much the supporting resource
+	// counts towards the progress of the goal, from 0 to 1.
+	ContributionWeight float64 `json:"contribution_weight,omitempty"`
+}
+
+type GoalSupportRequest struct {
+	// ResourceID is the gid of the goal, project,
+	// task or portfolio that supports the goal.
+	ResourceID string `json:"supporting_resource"`
+
+	ContributionWeight *float64 `json:"-"`
+
+	// InsertBefore and InsertAfter are the gids of subgoals
+	// to place a supporting goal before or after.
+	InsertBefore string `json:"insert_before,omitempty"`
+	InsertAfter  string `json:"insert_after,omitempty"`
+}
+
+// AddSupportingResource makes the goal, project, task or
+// portfolio in gsr count towards the progress of the goal.
+func (c *Client) AddSupportingResource(goalID string, gsr *GoalSupportRequest) (*GoalRelationship, error) {
+	goalID = strings.TrimSpace(goalID)
+	if goalID == "" {
+		return nil, errEmptyGoalID
+	}
+	if gsr == nil {
+		return nil, errNilGoalSupport
+	}
+	if strings.TrimSpace(gsr.ResourceID) == "" {
+		return nil, errEmptyResourceID
+	}
+	if gsr.InsertBefore != "" && gsr.InsertAfter != "" {
+		return nil, errBothBeforeAndAfter
+	}
+	qs, err := otils.ToURLValues(gsr)
+	if err != nil {
+		return nil, err
+	}
+	if gsr.ContributionWeight != nil {
+		qs.Set("contribution_weight", strconv.FormatFloat(*gsr.ContributionWeight, 'f', -1, 64))
+	}
+	slurp, err := c.doFormReq("POST", fmt.Sprintf("/goals/%s/addSupportingRelationship", goalID), qs)
+	if err != nil {
+		return nil, err
+	}
+	wrap := new(goalRelationshipWrap)
+	if err := json.Unmarshal(slurp, wrap); err != nil {
+		return nil, err
+	}
+	return wrap.GoalRelationship, nil
+}
+
+type goalRelationshipWrap struct {
+	GoalRelationship *GoalRelationship `json:"data"`
+}
+
+func (c *Client) RemoveSupportingResource(goalID, resourceID string) error {
+	goalID = strings.TrimSpace(goalID)
+	if goalID == "" {
+		return errEmptyGoalID
+	}
+	resourceID = strings.TrimSpace(resourceID)
+	if resourceID == "" {
+		return errEmptyResourceID
+	}
+	qs := make(url.Values)
+	qs.Set("supporting_resource", resourceID)
+	_, err := c.doFormReq("POST", fmt.Sprintf("/goals/%s/removeSupportingRelationship", goalID), qs)
+	return err
+}
+
+type GoalRelationshipsPage struct {
+	GoalRelationships []*GoalRelationship `json:"data"`
+	Err               error
+}
+
+type goalRelationshipsPager struct {
+	GoalRelationshipsPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+// ListGoalRelationships pages through the resources that support the goal.
+func (c *Client) ListGoalRelationships(goalID string) (pagesChan chan *GoalRelationshipsPage, cancelChan chan<- bool, err error) {
+	goalID = strings.TrimSpace(goalID)
+	if goalID == "" {
+		return nil, nil, errEmptyGoalID
+	}
+
+	pagesChan = make(chan *GoalRelationshipsPage)
+	path := fmt.Sprintf("/goal_relationships?%s", url.Values{"supported_goal": {goalID}}.Encode())
+	cancel := c.sendPages(pagesChan, path, func(slurp []byte) (interface{}, string, error) {
+		pager := new(goalRelationshipsPager)
+		if err := json.Unmarshal(slurp, pager); err != nil {
+			return nil, "", err
+		}
+		return &pager.GoalRelationshipsPage, pager.NextPage.nextPath(), nil
+	})
+	return pagesChan, cancel, nil
+}
+
+// GoalQuery filters the goals listed by ListGoals.
+// At least one of WorkspaceID, TeamID, PortfolioID
+// or ProjectID must be set.
+type GoalQuery struct {
+	WorkspaceID string `json:"workspace,omitempty"`
+	TeamID      string `json:"team,omitempty"`
+
+	// PortfolioID and ProjectID list the goals
+	// that the portfolio or project supports.
+	PortfolioID string `json:"portfolio,omitempty"`
+	ProjectID   string `json:"project,omitempty"`
+
+	// TimePeriods are the gids of the time
+	// periods to list the goals of.
+	TimePeriods []string `json:"-"`
+
+	IsWorkspaceLevel *bool `json:"-"`
+}
+
+func (gq *GoalQuery) Validate() error {
+	if gq == nil {
+		return errNilGoalQuery
+	}
+	for _, id := range []string{gq.WorkspaceID, gq.TeamID, gq.PortfolioID, gq.ProjectID} {
+		if strings.TrimSpace(id) != "" {
+			return nil
+		}
+	}
+	return errGoalQueryFilter
+}
+
+type GoalsPage struct {
+	Goals []*Goal `json:"data"`
+	Err   error
+}
+
+type goalsPager struct {
+	GoalsPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+func (c *Client) ListGoals(gq *GoalQuery) (pagesChan chan *GoalsPage, cancelChan chan<- bool, err error) {
+	if err := gq.Validate(); err != nil {
+		return nil, nil, err
+	}
+	qs, err := otils.ToURLValues(gq)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(gq.TimePeriods) > 0 {
+		qs.Set("time_periods", strings.Join(gq.TimePeriods, ","))
+	}
+	if gq.IsWorkspaceLevel != nil {
+		qs.Set("is_workspace_level", strconv.FormatBool(*gq.IsWorkspaceLevel))
+	}
+	return c.pageForGoals(fmt.Sprintf("/goals?%s", qs.Encode()))
+}
+
+// ParentGoals lists the goals that the goal supports.
+func (c *Client) ParentGoals(goalID string) (pagesChan chan *GoalsPage, cancelChan chan<- bool, err error) {
+	goalID = strings.TrimSpace(goalID)
+	if goalID == "" {
+		return nil, nil, errEmptyGoalID
+	}
+	return c.pageForGoals(fmt.Sprintf("/goals/%s/parentGoals", goalID))
+}
+
+func (c *Client) pageForGoals(path string) (pagesChan chan *GoalsPage, cancelChan chan<- bool, err error) {
+	pagesChan = make(chan *GoalsPage)
+	cancel := c.sendPages(pagesChan, path, func(slurp []byte) (interface{}, string, error) {
+		pager := new(goalsPager)
+		if err := json.Unmarshal(slurp, pager); err != nil {
+			return nil, "", err
+		}
+		return &pager.GoalsPage, pager.NextPage.nextPath(), nil
+	})
+	return pagesChan, cancel, nil
+}
@@ -0,0 +1,324 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/orijtech/asana/v1"
+)
+
+const goalID1 = "goal-1"
+
+func TestCreateGoal(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: goalsRoute})
+
+	workspaceLevel := true
+	tests := [...]struct {
+		req         *asana.GoalRequest
+		wantWSLevel bool
+		wantErr     bool
+	}{
+		0: {req: nil, wantErr: true},
+		1: {req: &asana.GoalRequest{Name: "Grow revenue"}, wantErr: true},
+		2: {req: &asana.GoalRequest{Workspace: workspaceID1, Name: " "}, wantErr: true},
+		3: {req: &asana.GoalRequest{Workspace: workspaceID1, Name: "Grow revenue", Team: teamID1}},
+		4: {
+			req:         &asana.GoalRequest{Workspace: workspaceID1, Name: "Grow revenue", IsWorkspaceLevel: &workspaceLevel},
+			wantWSLevel: true,
+		},
+	}
+
+	for i, tt := range tests {
+		goal, err := client.CreateGoal(tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		if goal.Name != tt.req.Name || goal.IsWorkspaceLevel != tt.wantWSLevel {
+			t.Errorf("#%d: got name=%q isWorkspaceLevel=%v", i, goal.Name, goal.IsWorkspaceLevel)
+		}
+	}
+
+	if _, err := client.UpdateGoal(&asana.GoalRequest{GoalID: goalID1, Workspace: workspaceID1}); err == nil {
+		t.Errorf("expected an error when changing the workspace of a goal")
+	}
+}
+
+func TestSetGoalMetric(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	b := &backend{route: goalsRoute}
+	client.SetHTTPRoundTripper(b)
+
+	tests := [...]struct {
+		goalID   string
+		req      *asana.GoalMetricRequest
+		wantForm map[string]string
+		wantErr  bool
+	}{
+		0: {goalID: goalID1, req: nil, wantErr: true},
+		1: {goalID: " ", req: &asana.GoalMetricRequest{Unit: asana.MetricNone}, wantErr: true},
+		2: {goalID: goalID1, req: &asana.GoalMetricRequest{TargetValue: 10}, wantErr: true},
+		3: {goalID: goalID1, req: &asana.GoalMetricRequest{Unit: asana.MetricCurrency, TargetValue: 10}, wantErr: true},
+		4: {
+			goalID: goalID1,
+			req: &asana.GoalMetricRequest{
+				Unit:         asana.MetricCurrency,
+				CurrencyCode: "EUR",
+				Precision:    2,
+				InitialValue: 0,
+				TargetValue:  1500,
+			},
+			wantForm: map[string]string{
+				"unit":                 "currency",
+				"currency_code":        "EUR",
+				"precision":            "2",
+				"initial_number_value": "0",
+				"target_number_value":  "1500",
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		b.lastForm = nil
+		goal, err := client.SetGoalMetric(tt.goalID, tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		if goal.Metric == nil || goal.Metric.Unit != tt.req.Unit {
+			t.Errorf("#%d: gotMetric=%#v wantUnit=%q", i, goal.Metric, tt.req.Unit)
+		}
+		for key, want := range tt.wantForm {
+			if got := b.lastForm.Get(key); got != want {
+				t.Errorf("#%d: %s: got %q want %q", i, key, got, want)
+			}
+		}
+		if _, ok := b.lastForm["metric_unit"]; ok {
+			t.Errorf("#%d: unexpectedly sent metric_unit", i)
+		}
+	}
+}
+
+func TestSetGoalMetricCurrentValue(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: goalsRoute})
+
+	if _, err := client.SetGoalMetricCurrentValue(" ", 10); err == nil {
+		t.Errorf("expected an error for an empty goalID")
+	}
+
+	goal, err := client.SetGoalMetricCurrentValue(goalID1, 62.5)
+	if err != nil {
+		t.Fatalf("setting the current value: %v", err)
+	}
+	if got, want := goal.Metric.CurrentValue, 62.5; got != want {
+		t.Errorf("gotValue=%v wantValue=%v", got, want)
+	}
+	if got, want := goal.Metric.Progress(), 0.625; got != want {
+		t.Errorf("gotProgress=%v wantProgress=%v", got, want)
+	}
+}
+
+func TestAddSupportingResource(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: goalsRoute})
+
+	weight := 0.5
+	tests := [...]struct {
+		req        *asana.GoalSupportRequest
+		wantWeight float64
+		wantErr    bool
+	}{
+		0: {req: nil, wantErr: true},
+		1: {req: &asana.GoalSupportRequest{ResourceID: " "}, wantErr: true},
+		2: {req: &asana.GoalSupportRequest{ResourceID: "goal-2", InsertBefore: "goal-3", InsertAfter: "goal-4"}, wantErr: true},
+		3: {req: &asana.GoalSupportRequest{ResourceID: projectID1}},
+		4: {req: &asana.GoalSupportRequest{ResourceID: taskID1, ContributionWeight: &weight}, wantWeight: 0.5},
+	}
+
+	for i, tt := range tests {
+		rel, err := client.AddSupportingResource(goalID1, tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		if rel.SupportingResource.GID != tt.req.ResourceID || rel.ContributionWeight != tt.wantWeight {
+			t.Errorf("#%d: got relationship %s", i, jsonMarshal(rel))
+		}
+	}
+
+	if err := client.RemoveSupportingResource(goalID1, projectID1); err != nil {
+		t.Errorf("removing a supporting resource: %v", err)
+	}
+}
+
+func TestListGoals(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: goalsRoute})
+
+	if _, _, err := client.ListGoals(&asana.GoalQuery{TimePeriods: []string{"tp-1"}}); err == nil {
+		t.Errorf("expected an error without a workspace, team, portfolio or project")
+	}
+
+	pagesChan, _, err := client.ListGoals(&asana.GoalQuery{
+		TeamID:      teamID1,
+		TimePeriods: []string{"tp-1", "tp-2"},
+	})
+	if err != nil {
+		t.Fatalf("listing goals: %v", err)
+	}
+	var gotGIDs []string
+	for page := range pagesChan {
+		if page.Err != nil {
+			t.Fatalf("page err: %v", page.Err)
+		}
+		for _, goal := range page.Goals {
+			gotGIDs = append(gotGIDs, goal.GID)
+		}
+	}
+	if want := []string{"goal-1", "goal-2"}; !reflect.DeepEqual(gotGIDs, want) {
+		t.Errorf("gotGIDs=%v wantGIDs=%v", gotGIDs, want)
+	}
+}
+
+func (b *backend) goalsRoundTrip(req *http.Request) (*http.Response, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/api/1.0")
+	if path == "/goals" && req.Method == "GET" {
+		if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		query := req.URL.Query()
+		if query.Get("team") != teamID1 || query.Get("time_periods") != "tp-1,tp-2" {
+			return makeResp("invalid goal query", http.StatusBadRequest, nil), nil
+		}
+		body := `{"data": [{"gid": "goal-1", "name": "Grow revenue"}],
+			"next_page": {"offset": "page-2", "path": "/goals?team=team-1&time_periods=tp-1,tp-2&offset=page-2"}}`
+		if query.Get("offset") == "page-2" {
+			body = `{"data": [{"gid": "goal-2", "name": "Hire"}], "next_page": null}`
+		}
+		return makeResp("200 OK", http.StatusOK, nopCloser(body)), nil
+	}
+
+	if badAuthResp, err := b.checkAuthorization(req, "POST"); err != nil || badAuthResp != nil {
+		return badAuthResp, err
+	}
+	if err := req.ParseForm(); err != nil {
+		return makeResp(err.Error(), http.StatusBadRequest, nil), nil
+	}
+	form := req.PostForm
+	b.lastForm = form
+
+	var body string
+	switch path {
+	case "/goals":
+		goal := &asana.Goal{
+			GID:              goalID1,
+			Name:             form.Get("name"),
+			IsWorkspaceLevel: form.Get("is_workspace_level") == "true",
+			Workspace:        &asana.NamedAndIDdEntity{GID: form.Get("workspace")},
+		}
+		body = fmt.Sprintf(`{"data": %s}`, jsonMarshal(goal))
+
+	case "/goals/" + goalID1 + "/setMetric":
+		target, err := strconv.ParseFloat(form.Get("target_number_value"), 64)
+		if err != nil {
+			return makeResp(err.Error(), http.StatusBadRequest, nil), nil
+		}
+		goal := &asana.Goal{
+			GID: goalID1,
+			Metric: &asana.GoalMetric{
+				Unit:        asana.MetricUnit(form.Get("unit")),
+				TargetValue: target,
+			},
+		}
+		body = fmt.Sprintf(`{"data": %s}`, jsonMarshal(goal))
+
+	case "/goals/" + goalID1 + "/setMetricCurrentValue":
+		value, err := strconv.ParseFloat(form.Get("current_number_value"), 64)
+		if err != nil {
+			return makeResp(err.Error(), http.StatusBadRequest, nil), nil
+		}
+		goal := &asana.Goal{
+			GID: goalID1,
+			Metric: &asana.GoalMetric{
+				Unit:         asana.MetricPercentage,
+				InitialValue: 0,
+				TargetValue:  100,
+				CurrentValue: value,
+			},
+		}
+		body = fmt.Sprintf(`{"data": %s}`, jsonMarshal(goal))
+
+	case "/goals/" + goalID1 + "/addSupportingRelationship":
+		if form.Get("supporting_resource") == "" || (form.Get("insert_before") != "" && form.Get("insert_after") != "") {
+			return makeResp("invalid supporting resource", http.StatusBadRequest, nil), nil
+		}
+		weight, _ := strconv.ParseFloat(form.Get("contribution_weight"), 64)
+		rel := &asana.GoalRelationship{
+			GID:                "rel-1",
+			SupportedGoal:      &asana.NamedAndIDdEntity{GID: goalID1},
+			SupportingResource: &asana.NamedAndIDdEntity{GID: form.Get("supporting_resource")},
+			ContributionWeight: weight,
+		}
+		body = fmt.Sprintf(`{"data": %s}`, jsonMarshal(rel))
+
+	case "/goals/" + goalID1 + "/removeSupportingRelationship":
+		body = `{"data": {}}`
+
+	default:
+		return unknownRouteResp, nil
+	}
+	return makeResp("200 OK", http.StatusOK, nopCloser(body)), nil
+}
@@ -0,0 +1,396 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type JobStatus string
+
+const (
+	JobNotStarted JobStatus = "not_started"
+	JobInProgress JobStatus = "in_progress"
+	JobSucceeded  JobStatus = "succeeded"
+	JobFailed     JobStatus = "failed"
+)
+
+// Job tracks an operation that Asana performs asynchronously,
+// such as duplicating a project or a task.
+type Job struct {
+	ID  int64  `json:"id,omitempty"`
+	GID string `json:"gid,omitempty"`
+
+	// Subtype is the kind of job, for example
+	// "duplicate_project" or "duplicate_task".
+	Subtype string `json:"resource_subtype,omitempty"`
+
+	Status JobStatus `json:"status,omitempty"`
+
+	// NewProject or NewTask references the resource that the job
+	// creates, by its gid and name only. It is usable once the
+	// job has succeeded.
+	NewProject *NamedAndIDdEntity `json:"new_project,omitempty"`
+	NewTask    *NamedAndIDdEntity `json:"new_task,omitempty"`
+}
+
+// Done reports whether the job has either succeeded or failed.
+func (j *Job) Done() bool {
+	return j != nil && (j.Status == JobSucceeded || j.Status == JobFailed)
+}
+
+var (
+	errEmptyJobID = errors.New("expecting a non-empty jobID")
+	errNoJob      = errors.New("no job was received")
+
+	// ErrJobFailed is returned by WaitForJob
+	// if the job finished without succeeding.
+	ErrJobFailed = errors.New("job failed")
+
+	// ErrJobTimedOut is returned by WaitForJob if
+	// the job was not done before the timeout.
+	ErrJobTimedOut = errors.New("timed out waiting for the job")
+)
+
+type jobWrap struct {
+	Job *Job `json:"data"`
+}
+
+func parseOutJobFromData(blob []byte) (*Job, error) {
+	jw := new(jobWrap)
+	if err := json.Unmarshal(blob, jw); err != nil {
+		return nil, err
+	}
+	if jw.Job == nil {
+		return nil, errNoJob
+	}
+	return jw.Job, nil
+}
+
+func (c *Client) FindJobByID(jobID string) (*Job, error) {
+	jobID = strings.TrimSpace(jobID)
+	if jobID == "" {
+		return nil, errEmptyJobID
+	}
+	return c.findJobBefore(jobID, time.Time{})
+}
+
+// findJobBefore fetches the job, giving up with ErrJobTimedOut
+// if the request is still in flight by the deadline. A zero
+// deadline means that the request is not bounded.
+func (c *Client) findJobBefore(jobID string, deadline time.Time) (*Job, error) {
+	fullURL := fmt.Sprintf("%s/jobs/%s", baseURL, jobID)
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !deadline.IsZero() {
+		ctx, cancel := context.WithDeadline(context.Background(), deadline)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		if !deadline.IsZero() && !time.Now().Before(deadline) {
+			return nil, ErrJobTimedOut
+		}
+		return nil, err
+	}
+	return parseOutJobFromData(slurp)
+}
+
+const (
+	defaultJobInitialBackoff = 500 * time.Millisecond
+	defaultJobMaxBackoff     = 10 * time.Second
+)
+
+type WaitOptions struct {
+	// InitialBackoff is the time to wait before polling the job
+	// for the first time. It doubles after every poll up to
+	// MaxBackoff. They default to 500ms and 10s respectively.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// Timeout if set, is the longest time to wait for the job.
+	Timeout time.Duration
+}
+
+// WaitForJob polls the job until it is done, backing off exponentially
+// between polls. It returns the finished job once it has succeeded. The
+// job only references the new project or task by its gid and name, use
+// WaitForDuplicateProject or WaitForDuplicateTask to fetch all of it.
+// If the job failed, it is returned along with ErrJobFailed. The Timeout
+// also bounds a poll that is still in flight.
+func (c *Client) WaitForJob(jobID string, wopts *WaitOptions) (*Job, error) {
+	jobID = strings.TrimSpace(jobID)
+	if jobID == "" {
+		return nil, errEmptyJobID
+	}
+	if wopts == nil {
+		wopts = new(WaitOptions)
+	}
+	backoff := wopts.InitialBackoff
+	if backoff <= 0 {
+		backoff = defaultJobInitialBackoff
+	}
+	maxBackoff := wopts.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultJobMaxBackoff
+	}
+	var deadline time.Time
+	if wopts.Timeout > 0 {
+		deadline = time.Now().Add(wopts.Timeout)
+	}
+
+	for {
+		if !deadline.IsZero() {
+			if remaining := deadline.Sub(time.Now()); remaining <= backoff {
+				time.Sleep(remaining)
+				return nil, ErrJobTimedOut
+			}
+		}
+		time.Sleep(backoff)
+
+		job, err := c.findJobBefore(jobID, deadline)
+		if err != nil {
+			return nil, err
+		}
+		switch job.Status {
+		case JobSucceeded:
+			return job, nil
+		case JobFailed:
+			return job, ErrJobFailed
+		}
+
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+var (
+	errNoNewProject = errors.New("expecting the job to have a new project")
+	errNoNewTask    = errors.New("expecting the job to have a new task")
+)
+
+// WaitForDuplicateProject waits for the job started by DuplicateProject
+// and then fetches the new project that it created.
+func (c *Client) WaitForDuplicateProject(jobID string, wopts *WaitOptions) (*Project, error) {
+	job, err := c.WaitForJob(jobID, wopts)
+	if err != nil {
+		return nil, err
+	}
+	if job.NewProject == nil || job.NewProject.GID == "" {
+		return nil, errNoNewProject
+	}
+	return c.FindProjectByID(job.NewProject.GID)
+}
+
+// WaitForDuplicateTask waits for the job started by DuplicateTask
+// and then fetches the new task that it created.
+func (c *Client) WaitForDuplicateTask(jobID string, wopts *WaitOptions) (*Task, error) {
+	job, err := c.WaitForJob(jobID, wopts)
+	if err != nil {
+		return nil, err
+	}
+	if job.NewTask == nil || job.NewTask.GID == "" {
+		return nil, errNoNewTask
+	}
+	return c.FindTaskByID(job.NewTask.GID)
+}
+
+// DuplicateOption selects the parts of a project or
+// task that are copied over when duplicating it.
+type DuplicateOption string
+
+const (
+	// The options for both projects and tasks.
+	DuplicateNotes DuplicateOption = "notes"
+
+	// The options for projects.
+	DuplicateMembers          DuplicateOption = "members"
+	DuplicateForms            DuplicateOption = "forms"
+	DuplicateTaskNotes        DuplicateOption = "task_notes"
+	DuplicateTaskAssignee     DuplicateOption = "task_assignee"
+	DuplicateTaskSubtasks     DuplicateOption = "task_subtasks"
+	DuplicateTaskAttachments  DuplicateOption = "task_attachments"
+	DuplicateTaskDates        DuplicateOption = "task_dates"
+	DuplicateTaskDependencies DuplicateOption = "task_dependencies"
+	DuplicateTaskFollowers    DuplicateOption = "task_followers"
+	DuplicateTaskTags         DuplicateOption = "task_tags"
+	DuplicateTaskProjects     DuplicateOption = "task_projects"
+	DuplicateTaskTemplates    DuplicateOption = "task_templates"
+
+	// The options for tasks.
+	DuplicateAssignee     DuplicateOption = "assignee"
+	DuplicateSubtasks     DuplicateOption = "subtasks"
+	DuplicateAttachments  DuplicateOption = "attachments"
+	DuplicateDates        DuplicateOption = "dates"
+	DuplicateDependencies DuplicateOption = "dependencies"
+	DuplicateFollowers    DuplicateOption = "followers"
+	DuplicateTags         DuplicateOption = "tags"
+	DuplicateProjects     DuplicateOption = "projects"
+	DuplicateParent       DuplicateOption = "parent"
+)
+
+func joinDuplicateOptions(include []DuplicateOption) string {
+	strs := make([]string, 0, len(include))
+	for _, opt := range include {
+		strs = append(strs, string(opt))
+	}
+	return strings.Join(strs, ",")
+}
+
+// ScheduleDates shifts the dates of the tasks of a duplicated
+// project so that it either ends on DueOn or begins on StartOn.
+// It requires DuplicateTaskDates to be included.
+type ScheduleDates struct {
+	DueOn   *Date
+	StartOn *Date
+
+	SkipWeekends bool
+}
+
+type ProjectDuplicateRequest struct {
+	// Name is the name of the new project.
+	Name string
+
+	// Team is the gid of the team to create the new project
+	// in. It defaults to the team of the original project.
+	Team string
+
+	Include []DuplicateOption
+
+	ScheduleDates *ScheduleDates
+}
+
+var (
+	errNilProjectDuplicateRequest = errors.New("expecting a non-nil projectDuplicateRequest")
+	errNilTaskDuplicateRequest    = errors.New("expecting a non-nil taskDuplicateRequest")
+	errEmptyDuplicateName         = errors.New("expecting a non-empty name for the duplicate")
+	errScheduleDatesAmbiguous     = errors.New("expecting exactly one of scheduleDates dueOn or startOn")
+	errScheduleDatesWithoutDates  = errors.New("scheduleDates requires the task_dates option")
+)
+
+func (pdr *ProjectDuplicateRequest) Validate() error {
+	if pdr == nil {
+		return errNilProjectDuplicateRequest
+	}
+	if strings.TrimSpace(pdr.Name) == "" {
+		return errEmptyDuplicateName
+	}
+	if sd := pdr.ScheduleDates; sd != nil {
+		if (sd.DueOn == nil) == (sd.StartOn == nil) {
+			return errScheduleDatesAmbiguous
+		}
+		hasDates := false
+		for _, opt := range pdr.Include {
+			if opt == DuplicateTaskDates {
+				hasDates = true
+			}
+		}
+		if !hasDates {
+			return errScheduleDatesWithoutDates
+		}
+	}
+	return nil
+}
+
+func (pdr *ProjectDuplicateRequest) toURLValues() url.Values {
+	qs := make(url.Values)
+	qs.Set("name", pdr.Name)
+	if team := strings.TrimSpace(pdr.Team); team != "" {
+		qs.Set("team", team)
+	}
+	if len(pdr.Include) > 0 {
+		qs.Set("include", joinDuplicateOptions(pdr.Include))
+	}
+	if sd := pdr.ScheduleDates; sd != nil {
+		if sd.DueOn != nil {
+			qs.Set("schedule_dates[due_on]", sd.DueOn.String())
+		}
+		if sd.StartOn != nil {
+			qs.Set("schedule_dates[start_on]", sd.StartOn.String())
+		}
+		qs.Set("schedule_dates[should_skip_weekends]", strconv.FormatBool(sd.SkipWeekends))
+	}
+	return qs
+}
+
+// DuplicateProject starts copying a project. The copy is made
+// asynchronously, use WaitForJob to get the new project.
+func (c *Client) DuplicateProject(projectID string, pdr *ProjectDuplicateRequest) (*Job, error) {
+	projectID = strings.TrimSpace(projectID)
+	if projectID == "" {
+		return nil, errEmptyProjectID
+	}
+	if err := pdr.Validate(); err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/projects/%s/duplicate", projectID)
+	slurp, err := c.doFormReq("POST", path, pdr.toURLValues())
+	if err != nil {
+		return nil, err
+	}
+	return parseOutJobFromData(slurp)
+}
+
+type TaskDuplicateRequest struct {
+	// Name is the name of the new task.
+	Name string
+
+	Include []DuplicateOption
+}
+
+func (tdr *TaskDuplicateRequest) Validate() error {
+	if tdr == nil {
+		return errNilTaskDuplicateRequest
+	}
+	if strings.TrimSpace(tdr.Name) == "" {
+		return errEmptyDuplicateName
+	}
+	return nil
+}
+
+// DuplicateTask starts copying a task. The copy is made
+// asynchronously, use WaitForJob to get the new task.
+func (c *Client) DuplicateTask(taskID string, tdr *TaskDuplicateRequest) (*Job, error) {
+	taskID = strings.TrimSpace(taskID)
+	if taskID == "" {
+		return nil, errEmptyTaskID
+	}
+	if err := tdr.Validate(); err != nil {
+		return nil, err
+	}
+	qs := make(url.Values)
+	qs.Set("name", tdr.Name)
+	if len(tdr.Include) > 0 {
+		qs.Set("include", joinDuplicateOptions(tdr.Include))
+	}
+	path := fmt.Sprintf("/tasks/%s/duplicate", taskID)
+	slurp, err := c.doFormReq("POST", path, qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutJobFromData(slurp)
+}
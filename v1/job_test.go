@@ -0,0 +1,230 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestDuplicateProject(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: jobsRoute})
+
+	dueOn := &asana.Date{Year: 2017, Month: time.October, Day: 2}
+	tests := [...]struct {
+		projectID string
+		req       *asana.ProjectDuplicateRequest
+		wantErr   bool
+	}{
+		0: {projectID: projectID1, req: nil, wantErr: true},
+		1: {projectID: "", req: &asana.ProjectDuplicateRequest{Name: "Launch II"}, wantErr: true},
+		2: {projectID: projectID1, req: &asana.ProjectDuplicateRequest{Name: "  "}, wantErr: true},
+		3: {
+			// Scheduling requires the task dates.
+			projectID: projectID1,
+			req: &asana.ProjectDuplicateRequest{
+				Name:          "Launch II",
+				ScheduleDates: &asana.ScheduleDates{DueOn: dueOn},
+			},
+			wantErr: true,
+		},
+		4: {
+			projectID: projectID1,
+			req: &asana.ProjectDuplicateRequest{
+				Name:          "Launch II",
+				Include:       []asana.DuplicateOption{asana.DuplicateTaskDates},
+				ScheduleDates: &asana.ScheduleDates{DueOn: dueOn, StartOn: dueOn},
+			},
+			wantErr: true,
+		},
+		5: {
+			projectID: projectID1,
+			req: &asana.ProjectDuplicateRequest{
+				Name:          "Launch II",
+				Include:       []asana.DuplicateOption{asana.DuplicateNotes, asana.DuplicateTaskDates},
+				ScheduleDates: &asana.ScheduleDates{DueOn: dueOn, SkipWeekends: true},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		job, err := client.DuplicateProject(tt.projectID, tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		if job.GID != "job-1" || job.Status != asana.JobNotStarted {
+			t.Errorf("#%d: got job %#v", i, job)
+		}
+	}
+}
+
+func TestWaitForJob(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: jobsRoute})
+
+	wopts := &asana.WaitOptions{
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+		Timeout:        500 * time.Millisecond,
+	}
+	tests := [...]struct {
+		jobID          string
+		wantErr        error
+		wantAnyErr     bool
+		wantNewProject string
+	}{
+		0: {jobID: "", wantAnyErr: true},
+		1: {jobID: "job-1", wantNewProject: "project-2"},
+		2: {jobID: "job-failed", wantErr: asana.ErrJobFailed},
+		3: {jobID: "job-stuck", wantErr: asana.ErrJobTimedOut},
+		4: {jobID: "job-unknown", wantAnyErr: true},
+		5: {jobID: "job-null", wantAnyErr: true},
+		6: {jobID: "job-slow", wantErr: asana.ErrJobTimedOut},
+	}
+
+	for i, tt := range tests {
+		start := time.Now()
+		job, err := client.WaitForJob(tt.jobID, wopts)
+		if elapsed := time.Since(start); elapsed > 2*wopts.Timeout {
+			t.Errorf("#%d: took %v, longer than the %v timeout", i, elapsed, wopts.Timeout)
+		}
+		if tt.wantAnyErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != tt.wantErr {
+			t.Errorf("#%d: gotErr=%v wantErr=%v", i, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if job.NewProject == nil || job.NewProject.GID != tt.wantNewProject {
+			t.Errorf("#%d: gotNewProject=%#v wantGID=%q", i, job.NewProject, tt.wantNewProject)
+		}
+	}
+}
+
+func TestWaitForDuplicateProject(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: jobsRoute})
+
+	wopts := &asana.WaitOptions{
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+		Timeout:        500 * time.Millisecond,
+	}
+	project, err := client.WaitForDuplicateProject("job-1", wopts)
+	if err != nil {
+		t.Fatalf("waiting for the project: %v", err)
+	}
+	if project.GID != "project-2" || project.Notes != "Copied from Launch" {
+		t.Errorf("got project %#v", project)
+	}
+
+	if _, err := client.WaitForDuplicateProject("job-failed", wopts); err != asana.ErrJobFailed {
+		t.Errorf("gotErr=%v wantErr=%v", err, asana.ErrJobFailed)
+	}
+}
+
+// jobsRoundTrip serves "job-1" which succeeds on its third poll,
+// "job-failed" which fails, "job-stuck" which never finishes and
+// "job-slow" whose polls hang until they are cancelled.
+func (b *backend) jobsRoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == "POST" {
+		if badAuthResp, err := b.checkAuthorization(req, "POST"); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		if !strings.HasSuffix(req.URL.Path, "/projects/"+projectID1+"/duplicate") {
+			return unknownRouteResp, nil
+		}
+		if err := req.ParseForm(); err != nil {
+			return makeResp(err.Error(), http.StatusBadRequest, nil), nil
+		}
+		want := map[string]string{
+			"name":                                 "Launch II",
+			"include":                              "notes,task_dates",
+			"schedule_dates[due_on]":               "2017-10-02",
+			"schedule_dates[should_skip_weekends]": "true",
+		}
+		for key, value := range want {
+			if got := req.PostForm.Get(key); got != value {
+				return makeResp(fmt.Sprintf("%s: got %q want %q", key, got, value), http.StatusBadRequest, nil), nil
+			}
+		}
+		body := `{"data": {"gid": "job-1", "resource_subtype": "duplicate_project", "status": "not_started"}}`
+		return makeResp("201 Created", http.StatusCreated, nopCloser(body)), nil
+	}
+
+	if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
+		return badAuthResp, err
+	}
+	if b.jobPolls == nil {
+		b.jobPolls = make(map[string]int)
+	}
+	if strings.HasSuffix(req.URL.Path, "/projects/project-2") {
+		body := `{"data": {"gid": "project-2", "name": "Launch II", "notes": "Copied from Launch"}}`
+		return makeResp("200 OK", http.StatusOK, nopCloser(body)), nil
+	}
+	jobID := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+	b.jobPolls[jobID] += 1
+
+	status, newProject := "in_progress", "null"
+	switch jobID {
+	case "job-1":
+		if b.jobPolls[jobID] >= 3 {
+			status, newProject = "succeeded", `{"gid": "project-2", "name": "Launch II"}`
+		}
+	case "job-failed":
+		status = "failed"
+	case "job-stuck":
+	case "job-slow":
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(5 * time.Second):
+		}
+	case "job-null":
+		return makeResp("200 OK", http.StatusOK, nopCloser(`{"data": null}`)), nil
+	default:
+		return makeResp("unknown job", http.StatusNotFound, nil), nil
+	}
+	body := fmt.Sprintf(`{"data": {"gid": %q, "status": %q, "new_project": %s}}`, jobID, status, newProject)
+	return makeResp("200 OK", http.StatusOK, nopCloser(body)), nil
+}
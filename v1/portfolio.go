@@ -0,0 +1,383 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/orijtech/otils"
+)
+
+// Portfolio is a collection of projects, and possibly other
+// portfolios, whose progress is tracked together.
+type Portfolio struct {
+	ID     int64  `json:"id,omitempty"`
+	GID    string `json:"gid,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Color  string `json:"color,omitempty"`
+	Public bool   `json:"public,omitempty"`
+
+	Owner     *NamedAndIDdEntity   `json:"owner,omitempty"`
+	Workspace *NamedAndIDdEntity   `json:"workspace,omitempty"`
+	Members   []*NamedAndIDdEntity `json:"members,omitempty"`
+
+	CreatedAt *time.Time         `json:"created_at,omitempty"`
+	CreatedBy *NamedAndIDdEntity `json:"created_by,omitempty"`
+
+	StartOn *Date `json:"start_on,omitempty"`
+	DueOn   *Date `json:"due_on,omitempty"`
+
+	CurrentStatusUpdate *StatusUpdate `json:"current_status_update,omitempty"`
+
+	CustomFields        []*CustomField        `json:"custom_fields,omitempty"`
+	CustomFieldSettings []*CustomFieldSetting `json:"custom_field_settings,omitempty"`
+
+	PermalinkURL string `json:"permalink_url,omitempty"`
+}
+
+type PortfolioRequest struct {
+	// PortfolioID is the gid of the portfolio to update.
+	PortfolioID string `json:"-"`
+
+	// Workspace is the gid of the workspace to create the portfolio
+	// in. It cannot be changed once the portfolio has been created.
+	Workspace string `json:"workspace,omitempty"`
+
+	Name  string `json:"name,omitempty"`
+	Color string `json:"color,omitempty"`
+
+	// Public if set, makes the portfolio visible
+	// or invisible to everyone in the workspace.
+	Public *bool `json:"-"`
+}
+
+var (
+	errNilPortfolioRequest = errors.New("expecting a non-nil portfolioRequest")
+	errEmptyPortfolioID    = errors.New("expecting a non-empty portfolioID")
+	errEmptyPortfolioName  = errors.New("expecting a non-empty portfolio name")
+	errNilPortfolioQuery   = errors.New("expecting a non-nil portfolioQuery")
+	errNilPortfolioItem    = errors.New("expecting a non-nil portfolioItemInsert")
+	errEmptyPortfolioItem  = errors.New("expecting a non-empty itemID")
+)
+
+func (preq *PortfolioRequest) Validate() error {
+	if preq == nil {
+		return errNilPortfolioRequest
+	}
+	if strings.TrimSpace(preq.Workspace) == "" {
+		return errEmptyWorkspace
+	}
+	if strings.TrimSpace(preq.Name) == "" {
+		return errEmptyPortfolioName
+	}
+	return nil
+}
+
+func (preq *PortfolioRequest) toURLValues() (url.Values, error) {
+	qs, err := otils.ToURLValues(preq)
+	if err != nil {
+		return nil, err
+	}
+	if preq.Public != nil {
+		qs.Set("public", strconv.FormatBool(*preq.Public))
+	}
+	return qs, nil
+}
+
+type portfolioWrap struct {
+	Portfolio *Portfolio `json:"data"`
+}
+
+func parseOutPortfolioFromData(blob []byte) (*Portfolio, error) {
+	pw := new(portfolioWrap)
+	if err := json.Unmarshal(blob, pw); err != nil {
+		return nil, err
+	}
+	return pw.Portfolio, nil
+}
+
+func (c *Client) CreatePortfolio(preq *PortfolioRequest) (*Portfolio, error) {
+	if err := preq.Validate(); err != nil {
+		return nil, err
+	}
+	qs, err := preq.toURLValues()
+	if err != nil {
+		return nil, err
+	}
+	slurp, err := c.doFormReq("POST", "/portfolios", qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutPortfolioFromData(slurp)
+}
+
+// UpdatePortfolio changes the attributes of a portfolio.
+// The Workspace of a portfolio cannot be changed once it
+// has been created and trying to will return an error.
+func (c *Client) UpdatePortfolio(preq *PortfolioRequest) (*Portfolio, error) {
+	if preq == nil {
+		return nil, errNilPortfolioRequest
+	}
+	portfolioID := strings.TrimSpace(preq.PortfolioID)
+	if portfolioID == "" {
+		return nil, errEmptyPortfolioID
+	}
+	if preq.Workspace != "" {
+		return nil, errImmutableWorkspace
+	}
+	qs, err := preq.toURLValues()
+	if err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/portfolios/%s", portfolioID)
+	slurp, err := c.doFormReq("PUT", path, qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutPortfolioFromData(slurp)
+}
+
+func (c *Client) FindPortfolioByID(portfolioID string) (*Portfolio, error) {
+	portfolioID = strings.TrimSpace(portfolioID)
+	if portfolioID == "" {
+		return nil, errEmptyPortfolioID
+	}
+	fullURL := fmt.Sprintf("%s/portfolios/%s", baseURL, portfolioID)
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutPortfolioFromData(slurp)
+}
+
+func (c *Client) DeletePortfolio(portfolioID string) error {
+	portfolioID = strings.TrimSpace(portfolioID)
+	if portfolioID == "" {
+		return errEmptyPortfolioID
+	}
+	fullURL := fmt.Sprintf("%s/portfolios/%s", baseURL, portfolioID)
+	req, err := http.NewRequest("DELETE", fullURL, nil)
+	if err != nil {
+		return err
+	}
+	_, _, err = c.doAuthReqThenSlurpBody(req)
+	return err
+}
+
+type PortfolioQuery struct {
+	// WorkspaceID is required.
+	WorkspaceID string `json:"workspace"`
+
+	// Owner is the user whose portfolios to list. Asana
+	// only lists your own portfolios so it defaults to "me".
+	Owner string `json:"owner"`
+}
+
+type PortfoliosPage struct {
+	Portfolios []*Portfolio `json:"data"`
+	Err        error
+}
+
+type portfoliosPager struct {
+	PortfoliosPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+func (c *Client) ListPortfolios(pq *PortfolioQuery) (pagesChan chan *PortfoliosPage, cancelChan chan<- bool, err error) {
+	if pq == nil {
+		return nil, nil, errNilPortfolioQuery
+	}
+	if strings.TrimSpace(pq.WorkspaceID) == "" {
+		return nil, nil, errEmptyWorkspace
+	}
+	copyQuery := *pq
+	if strings.TrimSpace(copyQuery.Owner) == "" {
+		copyQuery.Owner = MeAsUser
+	}
+	qs, err := otils.ToURLValues(&copyQuery)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagesChan = make(chan *PortfoliosPage)
+	path := fmt.Sprintf("/portfolios?%s", qs.Encode())
+	cancel := c.sendPages(pagesChan, path, func(slurp []byte) (interface{}, string, error) {
+		pager := new(portfoliosPager)
+		if err := json.Unmarshal(slurp, pager); err != nil {
+			return nil, "", err
+		}
+		return &pager.PortfoliosPage, pager.NextPage.nextPath(), nil
+	})
+	return pagesChan, cancel, nil
+}
+
+type PortfolioItemInsert struct {
+	// ItemID is the gid of the project or portfolio to add.
+	ItemID string `json:"item"`
+
+	// InsertBefore and InsertAfter are the gids of the items
+	// in the portfolio to place the item before or after. If
+	// neither is set, the item is added to the end.
+	InsertBefore string `json:"insert_before,omitempty"`
+	InsertAfter  string `json:"insert_after,omitempty"`
+}
+
+func (c *Client) AddItemToPortfolio(portfolioID string, pii *PortfolioItemInsert) error {
+	portfolioID = strings.TrimSpace(portfolioID)
+	if portfolioID == "" {
+		return errEmptyPortfolioID
+	}
+	if pii == nil {
+		return errNilPortfolioItem
+	}
+	if strings.TrimSpace(pii.ItemID) == "" {
+		return errEmptyPortfolioItem
+	}
+	if pii.InsertBefore != "" && pii.InsertAfter != "" {
+		return errBothBeforeAndAfter
+	}
+	qs, err := otils.ToURLValues(pii)
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/portfolios/%s/addItem", portfolioID)
+	_, err = c.doFormReq("POST", path, qs)
+	return err
+}
+
+func (c *Client) RemoveItemFromPortfolio(portfolioID, itemID string) error {
+	portfolioID = strings.TrimSpace(portfolioID)
+	if portfolioID == "" {
+		return errEmptyPortfolioID
+	}
+	itemID = strings.TrimSpace(itemID)
+	if itemID == "" {
+		return errEmptyPortfolioItem
+	}
+	qs := make(url.Values)
+	qs.Set("item", itemID)
+	path := fmt.Sprintf("/portfolios/%s/removeItem", portfolioID)
+	_, err := c.doFormReq("POST", path, qs)
+	return err
+}
+
+// ItemsForPortfolio pages through the projects in a portfolio.
+func (c *Client) ItemsForPortfolio(portfolioID string) (pagesChan chan *ProjectsPage, cancelChan chan<- bool, err error) {
+	portfolioID = strings.TrimSpace(portfolioID)
+	if portfolioID == "" {
+		return nil, nil, errEmptyPortfolioID
+	}
+
+	startPath := fmt.Sprintf("/portfolios/%s/items", portfolioID)
+	return c.pageForProjects(startPath)
+}
+
+func (c *Client) AddMembersToPortfolio(portfolioID string, userIDs ...string) (*Portfolio, error) {
+	return c.changePortfolioMembers("addMembers", portfolioID, userIDs)
+}
+
+func (c *Client) RemoveMembersFromPortfolio(portfolioID string, userIDs ...string) (*Portfolio, error) {
+	return c.changePortfolioMembers("removeMembers", portfolioID, userIDs)
+}
+
+func (c *Client) changePortfolioMembers(action, portfolioID string, userIDs []string) (*Portfolio, error) {
+	portfolioID = strings.TrimSpace(portfolioID)
+	if portfolioID == "" {
+		return nil, errEmptyPortfolioID
+	}
+	members := joinUserIDs(userIDs)
+	if members == "" {
+		return nil, errEmptyUserIDs
+	}
+	qs := make(url.Values)
+	qs.Set("members", members)
+	path := fmt.Sprintf("/portfolios/%s/%s", portfolioID, action)
+	slurp, err := c.doFormReq("POST", path, qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutPortfolioFromData(slurp)
+}
+
+// PortfolioMembership is the access that a user has to a portfolio.
+type PortfolioMembership struct {
+	ID  int64  `json:"id,omitempty"`
+	GID string `json:"gid,omitempty"`
+
+	User      *NamedAndIDdEntity `json:"user,omitempty"`
+	Portfolio *NamedAndIDdEntity `json:"portfolio,omitempty"`
+
+	AccessLevel AccessLevel `json:"access_level,omitempty"`
+}
+
+type PortfolioMembershipsPage struct {
+	Memberships []*PortfolioMembership `json:"data"`
+	Err         error
+}
+
+type portfolioMembershipsPager struct {
+	PortfolioMembershipsPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+func (c *Client) ListPortfolioMemberships(portfolioID string) (pagesChan chan *PortfolioMembershipsPage, cancelChan chan<- bool, err error) {
+	portfolioID = strings.TrimSpace(portfolioID)
+	if portfolioID == "" {
+		return nil, nil, errEmptyPortfolioID
+	}
+
+	pagesChan = make(chan *PortfolioMembershipsPage)
+	path := fmt.Sprintf("/portfolios/%s/portfolio_memberships?opt_fields=user.name,portfolio.name,access_level", portfolioID)
+	cancel := c.sendPages(pagesChan, path, func(slurp []byte) (interface{}, string, error) {
+		pager := new(portfolioMembershipsPager)
+		if err := json.Unmarshal(slurp, pager); err != nil {
+			return nil, "", err
+		}
+		return &pager.PortfolioMembershipsPage, pager.NextPage.nextPath(), nil
+	})
+	return pagesChan, cancel, nil
+}
+
+// AddCustomFieldSettingToPortfolio adds a custom field to a portfolio
+// so that it can be set on the projects in the portfolio.
+func (c *Client) AddCustomFieldSettingToPortfolio(portfolioID string, csr *CustomFieldSettingRequest) (*CustomFieldSetting, error) {
+	portfolioID = strings.TrimSpace(portfolioID)
+	if portfolioID == "" {
+		return nil, errEmptyPortfolioID
+	}
+	return c.addCustomFieldSetting(fmt.Sprintf("/portfolios/%s/addCustomFieldSetting", portfolioID), csr)
+}
+
+// RemoveCustomFieldSettingFromPortfolio removes a custom field from a portfolio.
+func (c *Client) RemoveCustomFieldSettingFromPortfolio(portfolioID, customFieldID string) error {
+	portfolioID = strings.TrimSpace(portfolioID)
+	if portfolioID == "" {
+		return errEmptyPortfolioID
+	}
+	return c.removeCustomFieldSetting(fmt.Sprintf("/portfolios/%s/removeCustomFieldSetting", portfolioID), customFieldID)
+}
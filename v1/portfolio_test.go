@@ -0,0 +1,196 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/orijtech/asana/v1"
+)
+
+const portfolioID1 = "portfolio-1"
+
+func TestCreatePortfolio(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: portfoliosRoute})
+
+	public := true
+	tests := [...]struct {
+		req        *asana.PortfolioRequest
+		wantPublic bool
+		wantErr    bool
+	}{
+		0: {req: nil, wantErr: true},
+		1: {req: &asana.PortfolioRequest{Name: "Q4"}, wantErr: true},
+		2: {req: &asana.PortfolioRequest{Workspace: workspaceID1, Name: " "}, wantErr: true},
+		3: {req: &asana.PortfolioRequest{Workspace: workspaceID1, Name: "Q4"}},
+		4: {req: &asana.PortfolioRequest{Workspace: workspaceID1, Name: "Q4", Public: &public}, wantPublic: true},
+	}
+
+	for i, tt := range tests {
+		portfolio, err := client.CreatePortfolio(tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		if portfolio.Name != tt.req.Name || portfolio.Public != tt.wantPublic {
+			t.Errorf("#%d: got name=%q public=%v", i, portfolio.Name, portfolio.Public)
+		}
+	}
+
+	if _, err := client.UpdatePortfolio(&asana.PortfolioRequest{PortfolioID: portfolioID1, Workspace: workspaceID1}); err == nil {
+		t.Errorf("expected an error when changing the workspace of a portfolio")
+	}
+}
+
+func TestPortfolioItems(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: portfoliosRoute})
+
+	inserts := [...]struct {
+		insert  *asana.PortfolioItemInsert
+		wantErr bool
+	}{
+		0: {insert: nil, wantErr: true},
+		1: {insert: &asana.PortfolioItemInsert{ItemID: " "}, wantErr: true},
+		2: {insert: &asana.PortfolioItemInsert{ItemID: "project-3", InsertBefore: "project-1", InsertAfter: "project-2"}, wantErr: true},
+		3: {insert: &asana.PortfolioItemInsert{ItemID: "project-3", InsertAfter: "project-2"}},
+	}
+	for i, tt := range inserts {
+		err := client.AddItemToPortfolio(portfolioID1, tt.insert)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+		} else if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+		}
+	}
+	if err := client.RemoveItemFromPortfolio(portfolioID1, "project-3"); err != nil {
+		t.Errorf("removing an item: %v", err)
+	}
+
+	pagesChan, _, err := client.ItemsForPortfolio(portfolioID1)
+	if err != nil {
+		t.Fatalf("listing items: %v", err)
+	}
+	var gotGIDs []string
+	for page := range pagesChan {
+		if page.Err != nil {
+			t.Fatalf("page err: %v", page.Err)
+		}
+		for _, project := range page.Projects {
+			gotGIDs = append(gotGIDs, project.GID)
+		}
+	}
+	if want := []string{"project-1", "project-2"}; !reflect.DeepEqual(gotGIDs, want) {
+		t.Errorf("gotGIDs=%v wantGIDs=%v", gotGIDs, want)
+	}
+}
+
+func TestAddCustomFieldSettingToPortfolio(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: portfoliosRoute})
+
+	setting, err := client.AddCustomFieldSettingToPortfolio(portfolioID1, &asana.CustomFieldSettingRequest{
+		CustomFieldID: "cf-1",
+		IsImportant:   true,
+	})
+	if err != nil {
+		t.Fatalf("adding the custom field: %v", err)
+	}
+	if setting.CustomField == nil || setting.CustomField.GID != "cf-1" || setting.Parent.GID != portfolioID1 {
+		t.Errorf("got setting %s", jsonMarshal(setting))
+	}
+	if err := client.RemoveCustomFieldSettingFromPortfolio(portfolioID1, "cf-1"); err != nil {
+		t.Errorf("removing the custom field: %v", err)
+	}
+}
+
+func (b *backend) portfoliosRoundTrip(req *http.Request) (*http.Response, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/api/1.0")
+	if path == "/portfolios/"+portfolioID1+"/items" {
+		if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		body := `{"data": [{"gid": "project-1", "name": "Launch"}],
+			"next_page": {"offset": "page-2", "path": "/portfolios/portfolio-1/items?offset=page-2"}}`
+		if req.URL.Query().Get("offset") == "page-2" {
+			body = `{"data": [{"gid": "project-2", "name": "Launch II"}], "next_page": null}`
+		}
+		return makeResp("200 OK", http.StatusOK, nopCloser(body)), nil
+	}
+
+	if badAuthResp, err := b.checkAuthorization(req, "POST"); err != nil || badAuthResp != nil {
+		return badAuthResp, err
+	}
+	if err := req.ParseForm(); err != nil {
+		return makeResp(err.Error(), http.StatusBadRequest, nil), nil
+	}
+	form := req.PostForm
+
+	var body string
+	switch path {
+	case "/portfolios":
+		portfolio := &asana.Portfolio{
+			GID:       portfolioID1,
+			Name:      form.Get("name"),
+			Public:    form.Get("public") == "true",
+			Workspace: &asana.NamedAndIDdEntity{GID: form.Get("workspace")},
+		}
+		body = fmt.Sprintf(`{"data": %s}`, jsonMarshal(portfolio))
+
+	case "/portfolios/" + portfolioID1 + "/addItem":
+		if form.Get("item") == "" || (form.Get("insert_before") != "" && form.Get("insert_after") != "") {
+			return makeResp("invalid item insert", http.StatusBadRequest, nil), nil
+		}
+		body = `{"data": {}}`
+
+	case "/portfolios/" + portfolioID1 + "/removeItem", "/portfolios/" + portfolioID1 + "/removeCustomFieldSetting":
+		body = `{"data": {}}`
+
+	case "/portfolios/" + portfolioID1 + "/addCustomFieldSetting":
+		setting := &asana.CustomFieldSetting{
+			GID:         "cfs-2",
+			CustomField: &asana.CustomField{GID: form.Get("custom_field")},
+			Parent:      &asana.NamedAndIDdEntity{GID: portfolioID1},
+			IsImportant: form.Get("is_important") == "true",
+		}
+		body = fmt.Sprintf(`{"data": %s}`, jsonMarshal(setting))
+
+	default:
+		return unknownRouteResp, nil
+	}
+	return makeResp("200 OK", http.StatusOK, nopCloser(body)), nil
+}
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -62,6 +63,9 @@ type ProjectRequest struct {
 	Name  string `json:"name,omitempty"`
 	Notes string `json:"notes,omitempty"`
 
+	// HTMLNotes is the rich text version of Notes.
+	HTMLNotes string `json:"html_notes,omitempty"`
+
 	Color  string `json:"color,omitempty"`
 	Layout Layout `json:"layout,omitempty"`
 
@@ -70,23 +74,67 @@ type ProjectRequest struct {
 	Workspace string `json:"workspace,omitempty"`
 
 	PublicToOrganization bool `json:"public,omitempty"`
+
+	Icon        string `json:"icon,omitempty"`
+	DefaultView string `json:"default_view,omitempty"`
+
+	// StartOn can only be set along with DueOn.
+	StartOn *Date `json:"start_on,omitempty"`
+	DueOn   *Date `json:"due_on,omitempty"`
+
+	// Completed if set, marks the project as complete or incomplete.
+	Completed *bool `json:"-"`
+
+	// CustomFields maps the gids of custom fields to the values to set.
+	// The fields must have been added to the project beforehand.
+	CustomFields CustomFieldValues `json:"-"`
 }
 
 type Project struct {
 	ID       int64  `json:"id,omitempty"`
+	GID      string `json:"gid,omitempty"`
 	Name     string `json:"name,omitempty"`
 	Notes    string `json:"notes,omitempty"`
 	Color    string `json:"color,omitempty"`
+	Icon     string `json:"icon,omitempty"`
 	Archived bool   `json:"archived,omitempty"`
+	Public   bool   `json:"public,omitempty"`
+
+	HTMLNotes string `json:"html_notes,omitempty"`
+
+	// DefaultView is the view that the project opens
+	// in: "list", "board", "calendar" or "timeline".
+	DefaultView string `json:"default_view,omitempty"`
 
 	Owner      *NamedAndIDdEntity `json:"owner,omitempty"`
 	CreatedAt  *time.Time         `json:"created_at,omitempty"`
-	ModifiedAt *time.Time         `json:"created_at,omitempty"`
+	ModifiedAt *time.Time         `json:"modified_at,omitempty"`
+
+	StartOn *Date `json:"start_on,omitempty"`
+	DueOn   *Date `json:"due_on,omitempty"`
+
+	Completed   bool               `json:"completed,omitempty"`
+	CompletedAt *time.Time         `json:"completed_at,omitempty"`
+	CompletedBy *NamedAndIDdEntity `json:"completed_by,omitempty"`
 
 	Workspace *NamedAndIDdEntity `json:"workspace,omitempty"`
+	Team      *NamedAndIDdEntity `json:"team,omitempty"`
 
 	Members   []*NamedAndIDdEntity `json:"members,omitempty"`
 	Followers []*NamedAndIDdEntity `json:"followers,omitempty"`
+
+	// CurrentStatus is the latest status posted to the project, in
+	// the legacy format that sets Color instead of StatusType.
+	CurrentStatus *StatusUpdate `json:"current_status,omitempty"`
+
+	// CurrentStatusUpdate is the latest status update posted to the
+	// project. It is compact, use FindStatusUpdateByID for its text.
+	CurrentStatusUpdate *StatusUpdate `json:"current_status_update,omitempty"`
+
+	CustomFields        []*CustomField        `json:"custom_fields,omitempty"`
+	CustomFieldSettings []*CustomFieldSetting `json:"custom_field_settings,omitempty"`
+
+	PermalinkURL string `json:"permalink_url,omitempty"`
 }
 
 var (
@@ -101,9 +149,37 @@ func (preq *ProjectRequest) Validate() error {
 	if preq.Workspace == "" {
 		return errEmptyWorkspace
 	}
+	if err := preq.validateDates(); err != nil {
+		return err
+	}
+	return validateRichText(preq.HTMLNotes)
+}
+
+func (preq *ProjectRequest) validateDates() error {
+	if preq.StartOn == nil {
+		return nil
+	}
+	if preq.DueOn == nil {
+		return errStartWithoutDue
+	}
+	if preq.StartOn.After(*preq.DueOn) {
+		return errStartAfterDue
+	}
 	return nil
 }
 
+func (preq *ProjectRequest) toURLValues() (url.Values, error) {
+	qs, err := otils.ToURLValues(preq)
+	if err != nil {
+		return nil, err
+	}
+	if preq.Completed != nil {
+		qs.Set("completed", strconv.FormatBool(*preq.Completed))
+	}
+	preq.CustomFields.addTo(qs)
+	return qs, nil
+}
+
 type projectWrap struct {
 	Project *Project `json:"data"`
 }
@@ -133,13 +209,19 @@ func (c *Client) UpdateProject(preq *ProjectRequest) (*Project, error) {
 	if preq.Workspace != "" {
 		return nil, errImmutableWorkspace
 	}
+	if err := preq.validateDates(); err != nil {
+		return nil, err
+	}
+	if err := validateRichText(preq.HTMLNotes); err != nil {
+		return nil, err
+	}
 
 	copyReq := *preq
 	// Now unset ProjectID to avoid problems
 	// with trying to mutate it on the backend.
 	copyReq.ProjectID = ""
 
-	qs, err := otils.ToURLValues(&copyReq)
+	qs, err := copyReq.toURLValues()
 	if err != nil {
 		return nil, err
 	}
@@ -164,7 +246,7 @@ func (c *Client) CreateProject(preq *ProjectRequest) (*Project, error) {
 		return nil, err
 	}
 
-	qs, err := otils.ToURLValues(preq)
+	qs, err := preq.toURLValues()
 	if err != nil {
 		return nil, err
 	}
@@ -244,40 +326,7 @@ func (c *Client) QueryForProjects(pq *ProjectQuery) (pagesChan chan *ProjectsPag
 		return nil, nil, err
 	}
 
-	cancelChan = make(chan bool, 1)
-	pagesChan = make(chan *ProjectsPage)
-
-	go func() {
-		defer close(pagesChan)
-
-		path := fmt.Sprintf("/projects?%s", qs.Encode())
-		for {
-			fullURL := fmt.Sprintf("%s%s", baseURL, path)
-			req, _ := http.NewRequest("GET", fullURL, nil)
-			slurp, _, err := c.doAuthReqThenSlurpBody(req)
-			if err != nil {
-				pagesChan <- &ProjectsPage{Err: err}
-				return
-			}
-
-			page := new(projectsPager)
-			if err := json.Unmarshal(slurp, page); err != nil {
-				page.Err = err
-			}
-
-			pp := page.ProjectsPage
-			pagesChan <- &pp
-
-			if np := page.NextPage; np != nil && np.Path == "" {
-				path = np.Path
-			} else {
-				// End of this pagination
-				break
-			}
-		}
-	}()
-
-	return pagesChan, cancelChan, nil
+	return c.pageForProjects(fmt.Sprintf("/projects?%s", qs.Encode()))
 }
 
 func (c *Client) TasksForProject(projectID string) (resultsChan chan *TaskResultPage, cancelChan chan<- bool, err error) {
@@ -288,3 +337,121 @@ func (c *Client) TasksForProject(projectID string) (resultsChan chan *TaskResult
 	startPath := fmt.Sprintf("/projects/%s/tasks", projectID)
 	return c.doTasksPaging(startPath)
 }
+
+var errEmptyUserIDs = errors.New("expecting at least one non-empty userID")
+
+// AddMembersToProject gives the users access to the project.
+// It returns the project with its updated members.
+func (c *Client) AddMembersToProject(projectID string, userIDs ...string) (*Project, error) {
+	return c.changeProjectUsers("addMembers", "members", projectID, userIDs)
+}
+
+func (c *Client) RemoveMembersFromProject(projectID string, userIDs ...string) (*Project, error) {
+	return c.changeProjectUsers("removeMembers", "members", projectID, userIDs)
+}
+
+// AddFollowersToProject makes the users follow the project, also
+// adding them as members if they are not members already.
+func (c *Client) AddFollowersToProject(projectID string, userIDs ...string) (*Project, error) {
+	return c.changeProjectUsers("addFollowers", "followers", projectID, userIDs)
+}
+
+// RemoveFollowersFromProject stops the users from following
+// the project without removing them as members.
+func (c *Client) RemoveFollowersFromProject(projectID string, userIDs ...string) (*Project, error) {
+	return c.changeProjectUsers("removeFollowers", "followers", projectID, userIDs)
+}
+
+func (c *Client) changeProjectUsers(action, key, projectID string, userIDs []string) (*Project, error) {
+	projectID = strings.TrimSpace(projectID)
+	if projectID == "" {
+		return nil, errEmptyProjectID
+	}
+	users := joinUserIDs(userIDs)
+	if users == "" {
+		return nil, errEmptyUserIDs
+	}
+	qs := make(url.Values)
+	qs.Set(key, users)
+	path := fmt.Sprintf("/projects/%s/%s", projectID, action)
+	slurp, err := c.doFormReq("POST", path, qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutProjectFromData(slurp)
+}
+
+// joinUserIDs comma separates the non-blank userIDs.
+func joinUserIDs(userIDs []string) string {
+	var nonBlank []string
+	for _, userID := range userIDs {
+		if userID = strings.TrimSpace(userID); userID != "" {
+			nonBlank = append(nonBlank, userID)
+		}
+	}
+	return strings.Join(nonBlank, ",")
+}
+
+type AccessLevel string
+
+const (
+	AccessAdmin     AccessLevel = "admin"
+	AccessEditor    AccessLevel = "editor"
+	AccessCommenter AccessLevel = "commenter"
+	AccessViewer    AccessLevel = "viewer"
+)
+
+// ProjectMembership is the access that a user has to a project.
+type ProjectMembership struct {
+	ID  int64  `json:"id,omitempty"`
+	GID string `json:"gid,omitempty"`
+
+	User    *NamedAndIDdEntity `json:"user,omitempty"`
+	Project *NamedAndIDdEntity `json:"project,omitempty"`
+
+	AccessLevel AccessLevel `json:"access_level,omitempty"`
+
+	// WriteAccess is either "full_write" or "comment_only".
+	WriteAccess string `json:"write_access,omitempty"`
+}
+
+type ProjectMembershipsPage struct {
+	Memberships []*ProjectMembership `json:"data"`
+	Err         error
+}
+
+type projectMembershipsPager struct {
+	ProjectMembershipsPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+func (c *Client) ListProjectMemberships(projectID string) (pagesChan chan *ProjectMembershipsPage, cancelChan chan<- bool, err error) {
+	projectID = strings.TrimSpace(projectID)
+	if projectID == "" {
+		return nil, nil, errEmptyProjectID
+	}
+
+	pagesChan = make(chan *ProjectMembershipsPage)
+	path := fmt.Sprintf("/projects/%s/project_memberships?opt_fields=user.name,project.name,access_level,write_access", projectID)
+	cancel := c.sendPages(pagesChan, path, func(slurp []byte) (interface{}, string, error) {
+		pager := new(projectMembershipsPager)
+		if err := json.Unmarshal(slurp, pager); err != nil {
+			return nil, "", err
+		}
+		return &pager.ProjectMembershipsPage, pager.NextPage.nextPath(), nil
+	})
+	return pagesChan, cancel, nil
+}
+
+func (c *Client) pageForProjects(path string) (pagesChan chan *ProjectsPage, cancelChan chan<- bool, err error) {
+	pagesChan = make(chan *ProjectsPage)
+	cancel := c.sendPages(pagesChan, path, func(slurp []byte) (interface{}, string, error) {
+		pager := new(projectsPager)
+		if err := json.Unmarshal(slurp, pager); err != nil {
+			return nil, "", err
+		}
+		return &pager.ProjectsPage, pager.NextPage.nextPath(), nil
+	})
+	return pagesChan, cancel, nil
+}
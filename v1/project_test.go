@@ -0,0 +1,347 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestChangeProjectMembers(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: projectMembersRoute})
+
+	tests := [...]struct {
+		change      func(string, ...string) (*asana.Project, error)
+		projectID   string
+		userIDs     []string
+		wantMembers []string
+		wantErr     bool
+	}{
+		0: {change: client.AddMembersToProject, projectID: "", userIDs: []string{"7"}, wantErr: true},
+		1: {change: client.AddMembersToProject, projectID: projectID1, wantErr: true},
+		2: {change: client.AddMembersToProject, projectID: projectID1, userIDs: []string{" ", ""}, wantErr: true},
+		3: {
+			change: client.AddMembersToProject, projectID: projectID1,
+			userIDs: []string{"7", " 8 "}, wantMembers: []string{"addMembers:7", "addMembers:8"},
+		},
+		4: {
+			change: client.RemoveMembersFromProject, projectID: projectID1,
+			userIDs: []string{"8"}, wantMembers: []string{"removeMembers:8"},
+		},
+		5: {
+			change: client.AddFollowersToProject, projectID: projectID1,
+			userIDs: []string{"9"}, wantMembers: []string{"addFollowers:9"},
+		},
+		6: {
+			change: client.RemoveFollowersFromProject, projectID: projectID1,
+			userIDs: []string{"9"}, wantMembers: []string{"removeFollowers:9"},
+		},
+	}
+
+	for i, tt := range tests {
+		project, err := tt.change(tt.projectID, tt.userIDs...)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		var gotMembers []string
+		for _, member := range project.Members {
+			gotMembers = append(gotMembers, member.Name)
+		}
+		if !reflect.DeepEqual(gotMembers, tt.wantMembers) {
+			t.Errorf("#%d: gotMembers=%v wantMembers=%v", i, gotMembers, tt.wantMembers)
+		}
+	}
+}
+
+func TestListProjectMemberships(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: projectMembersRoute})
+
+	pagesChan, _, err := client.ListProjectMemberships(projectID1)
+	if err != nil {
+		t.Fatalf("listing memberships: %v", err)
+	}
+	got := make(map[string]asana.AccessLevel)
+	for page := range pagesChan {
+		if page.Err != nil {
+			t.Fatalf("page err: %v", page.Err)
+		}
+		for _, membership := range page.Memberships {
+			got[membership.User.Name] = membership.AccessLevel
+		}
+	}
+	want := map[string]asana.AccessLevel{"Emeka": asana.AccessAdmin, "Ada": asana.AccessCommenter}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got=%v want=%v", got, want)
+	}
+}
+
+// projectMembersRoundTrip responds to membership changes with a project
+// whose members are named after the action and the users it was sent.
+func (b *backend) projectMembersRoundTrip(req *http.Request) (*http.Response, error) {
+	prefix := "/api/1.0/projects/" + projectID1 + "/"
+	if !strings.HasPrefix(req.URL.Path, prefix) {
+		return unknownRouteResp, nil
+	}
+	action := strings.TrimPrefix(req.URL.Path, prefix)
+	if action == "project_memberships" {
+		if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		return makeRespFromFile(fmt.Sprintf("./testdata/project-memberships-%s.json", projectID1))
+	}
+
+	if badAuthResp, err := b.checkAuthorization(req, "POST"); err != nil || badAuthResp != nil {
+		return badAuthResp, err
+	}
+	if err := req.ParseForm(); err != nil {
+		return makeResp(err.Error(), http.StatusBadRequest, nil), nil
+	}
+	var key string
+	switch action {
+	case "addMembers", "removeMembers":
+		key = "members"
+	case "addFollowers", "removeFollowers":
+		key = "followers"
+	default:
+		return unknownRouteResp, nil
+	}
+	project := &asana.Project{Name: "Launch"}
+	for _, userID := range strings.Split(req.PostForm.Get(key), ",") {
+		project.Members = append(project.Members, &asana.NamedAndIDdEntity{Name: action + ":" + userID})
+	}
+	body := fmt.Sprintf(`{"data": %s}`, jsonMarshal(project))
+	return makeResp("200 OK", http.StatusOK, nopCloser(body)), nil
+}
+
+func TestFindProjectByID(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: projectsRoute})
+
+	project, err := client.FindProjectByID(projectID1)
+	if err != nil {
+		t.Fatalf("finding the project: %v", err)
+	}
+
+	if project.ModifiedAt == nil || project.CreatedAt == nil || project.ModifiedAt.Equal(*project.CreatedAt) {
+		t.Errorf("modifiedAt was not decoded separately: createdAt=%v modifiedAt=%v", project.CreatedAt, project.ModifiedAt)
+	}
+	wantDueOn := asana.Date{Year: 2017, Month: time.September, Day: 29}
+	if project.DueOn == nil || *project.DueOn != wantDueOn {
+		t.Errorf("gotDueOn=%v wantDueOn=%v", project.DueOn, wantDueOn)
+	}
+	wantStartOn := asana.Date{Year: 2017, Month: time.June, Day: 5}
+	if project.StartOn == nil || *project.StartOn != wantStartOn {
+		t.Errorf("gotStartOn=%v wantStartOn=%v", project.StartOn, wantStartOn)
+	}
+	if project.CurrentStatus == nil || project.CurrentStatus.Color != "green" {
+		t.Errorf("gotCurrentStatus=%#v", project.CurrentStatus)
+	}
+	if project.CurrentStatusUpdate == nil || project.CurrentStatusUpdate.GID != "801" {
+		t.Errorf("gotCurrentStatusUpdate=%#v", project.CurrentStatusUpdate)
+	}
+	if project.Team == nil || project.Team.GID != "team-1" {
+		t.Errorf("gotTeam=%#v", project.Team)
+	}
+	if len(project.CustomFields) != 1 || project.CustomFields[0].NumberValue == nil {
+		t.Errorf("gotCustomFields=%#v", project.CustomFields)
+	}
+	if len(project.CustomFieldSettings) != 1 || !project.CustomFieldSettings[0].IsImportant {
+		t.Errorf("gotCustomFieldSettings=%#v", project.CustomFieldSettings)
+	}
+	if !project.Completed || project.DefaultView != "timeline" || project.Icon != "rocket" {
+		t.Errorf("got completed=%v defaultView=%q icon=%q", project.Completed, project.DefaultView, project.Icon)
+	}
+	if want := "https://app.asana.com/0/project-1/list"; project.PermalinkURL != want {
+		t.Errorf("gotPermalinkURL=%q wantPermalinkURL=%q", project.PermalinkURL, want)
+	}
+
+	// Encoding the project and decoding it back must not lose anything.
+	roundTripped := new(asana.Project)
+	if err := json.Unmarshal(jsonMarshal(project), roundTripped); err != nil {
+		t.Fatalf("decoding the encoded project: %v", err)
+	}
+	if !reflect.DeepEqual(roundTripped, project) {
+		t.Errorf("round trip mismatch:\ngot:  %s\nwant: %s", jsonMarshal(roundTripped), jsonMarshal(project))
+	}
+}
+
+func TestQueryForProjects(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: projectsRoute})
+
+	if _, _, err := client.QueryForProjects(nil); err == nil {
+		t.Errorf("expected an error for a nil query")
+	}
+
+	pagesChan, _, err := client.QueryForProjects(&asana.ProjectQuery{WorkspaceID: workspaceID1})
+	if err != nil {
+		t.Fatalf("querying projects: %v", err)
+	}
+	var gotGIDs []string
+	for page := range pagesChan {
+		if page.Err != nil {
+			t.Fatalf("page err: %v", page.Err)
+		}
+		for _, project := range page.Projects {
+			gotGIDs = append(gotGIDs, project.GID)
+		}
+	}
+	if want := []string{projectID1, "project-2"}; !reflect.DeepEqual(gotGIDs, want) {
+		t.Errorf("gotGIDs=%v wantGIDs=%v", gotGIDs, want)
+	}
+}
+
+func TestUpdateProject(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	b := &backend{route: projectsRoute}
+	client.SetHTTPRoundTripper(b)
+
+	completed := true
+	tests := [...]struct {
+		req      *asana.ProjectRequest
+		wantForm map[string]string
+		wantErr  bool
+	}{
+		0: {req: nil, wantErr: true},
+		1: {req: &asana.ProjectRequest{Name: "Launch"}, wantErr: true},
+		2: {req: &asana.ProjectRequest{ProjectID: projectID1, Workspace: workspaceID1}, wantErr: true},
+		3: {
+			// A start date requires a due date.
+			req: &asana.ProjectRequest{
+				ProjectID: projectID1,
+				StartOn:   &asana.Date{Year: 2017, Month: time.June, Day: 5},
+			},
+			wantErr: true,
+		},
+		4: {
+			req: &asana.ProjectRequest{
+				ProjectID: projectID1,
+				StartOn:   &asana.Date{Year: 2017, Month: time.October, Day: 5},
+				DueOn:     &asana.Date{Year: 2017, Month: time.September, Day: 29},
+			},
+			wantErr: true,
+		},
+		5: {
+			req: &asana.ProjectRequest{
+				ProjectID:    projectID1,
+				StartOn:      &asana.Date{Year: 2017, Month: time.June, Day: 5},
+				DueOn:        &asana.Date{Year: 2017, Month: time.September, Day: 29},
+				DefaultView:  "timeline",
+				Completed:    &completed,
+				CustomFields: asana.CustomFieldValues{"cf-1": asana.CustomNumber(1500)},
+			},
+			wantForm: map[string]string{
+				"start_on":            "2017-06-05",
+				"due_on":              "2017-09-29",
+				"default_view":        "timeline",
+				"completed":           "true",
+				"custom_fields[cf-1]": "1500",
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		b.lastForm = nil
+		project, err := client.UpdateProject(tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		if project.GID != projectID1 {
+			t.Errorf("#%d: gotGID=%q wantGID=%q", i, project.GID, projectID1)
+		}
+		for key, want := range tt.wantForm {
+			if got := b.lastForm.Get(key); got != want {
+				t.Errorf("#%d: %s: got %q want %q", i, key, got, want)
+			}
+		}
+	}
+}
+
+// projectsRoundTrip serves the recorded project for GET requests and
+// records the form of PUT requests in the backend.
+func (b *backend) projectsRoundTrip(req *http.Request) (*http.Response, error) {
+	if strings.HasSuffix(req.URL.Path, "/projects") {
+		if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		query := req.URL.Query()
+		if query.Get("workspace") != workspaceID1 {
+			return makeResp("expecting the workspace filter", http.StatusBadRequest, nil), nil
+		}
+		body := `{"data": [{"gid": "project-1"}], "next_page": {"offset": "page-2", "path": "/projects?offset=page-2&workspace=workspace-1"}}`
+		if query.Get("offset") == "page-2" {
+			body = `{"data": [{"gid": "project-2"}], "next_page": null}`
+		}
+		return makeResp("200 OK", http.StatusOK, nopCloser(body)), nil
+	}
+	if !strings.HasSuffix(req.URL.Path, "/projects/"+projectID1) {
+		return unknownRouteResp, nil
+	}
+	if req.Method == "GET" {
+		if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		return makeRespFromFile(fmt.Sprintf("./testdata/project-%s.json", projectID1))
+	}
+
+	if badAuthResp, err := b.checkAuthorization(req, "PUT"); err != nil || badAuthResp != nil {
+		return badAuthResp, err
+	}
+	if err := req.ParseForm(); err != nil {
+		return makeResp(err.Error(), http.StatusBadRequest, nil), nil
+	}
+	b.lastForm = req.PostForm
+	project := &asana.Project{GID: projectID1}
+	body := fmt.Sprintf(`{"data": %s}`, jsonMarshal(project))
+	return makeResp("200 OK", http.StatusOK, nopCloser(body)), nil
+}
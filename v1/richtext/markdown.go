@@ -0,0 +1,474 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package richtext
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Options customizes the conversion from Markdown.
+type Options struct {
+	// Mentions maps the @handles used in the Markdown
+	// to the gids of the users that they refer to.
+	// A handle made up only of digits is always
+	// treated as a gid and does not need an entry.
+	Mentions map[string]string
+}
+
+func (opts *Options) mentionGID(handle string) (string, bool) {
+	if isAllDigits(handle) {
+		return handle, true
+	}
+	if opts == nil {
+		return "", false
+	}
+	gid, ok := opts.Mentions[handle]
+	return gid, ok
+}
+
+var (
+	reHeading     = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	reRule        = regexp.MustCompile(`^\s*(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	reListItem    = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	reBlockquote  = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	reFence       = regexp.MustCompile("^\\s*(`{3,})")
+	reAsanaTask   = regexp.MustCompile(`^https://app\.asana\.com/0/\d+/(\d+)(?:/f)?/?$`)
+	reMentionName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*`)
+)
+
+// FromMarkdown converts Markdown into Asana's rich text format.
+//
+// Besides the usual emphasis, code, lists, headings, quotes and
+// links, @handles are turned into user mentions and links to
+// Asana tasks are turned into task references.
+//
+// Asana only has two levels of headings so "###" and deeper
+// headings are converted to <h2> just like "##" headings.
+func FromMarkdown(md string, opts *Options) (string, error) {
+	md = strings.Replace(md, "\r\n", "\n", -1)
+	lines := strings.Split(md, "\n")
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("<body>")
+	buf.WriteString(convertBlocks(lines, opts))
+	buf.WriteString("</body>")
+
+	doc := buf.String()
+	if err := Validate(doc); err != nil {
+		return "", err
+	}
+	return doc, nil
+}
+
+func convertBlocks(lines []string, opts *Options) string {
+	buf := new(bytes.Buffer)
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case reFence.MatchString(line):
+			fence := reFence.FindStringSubmatch(line)[1]
+			i++
+			var code []string
+			for i < len(lines) && !closesFence(lines[i], fence) {
+				code = append(code, lines[i])
+				i++
+			}
+			// Skip the closing fence.
+			i++
+			buf.WriteString("<pre>")
+			buf.WriteString(escape(strings.Join(code, "\n")))
+			buf.WriteString("</pre>")
+
+		case reRule.MatchString(line):
+			buf.WriteString("<hr/>")
+			i++
+
+		case reHeading.MatchString(line):
+			matches := reHeading.FindStringSubmatch(line)
+			tag := "h2"
+			if len(matches[1]) == 1 {
+				tag = "h1"
+			}
+			buf.WriteString("<" + tag + ">")
+			buf.WriteString(convertInline(strings.TrimSpace(matches[2]), opts))
+			buf.WriteString("</" + tag + ">")
+			i++
+
+		case reBlockquote.MatchString(line):
+			var quoted []string
+			for i < len(lines) && reBlockquote.MatchString(lines[i]) {
+				quoted = append(quoted, reBlockquote.FindStringSubmatch(lines[i])[1])
+				i++
+			}
+			buf.WriteString("<blockquote>")
+			buf.WriteString(convertBlocks(quoted, opts))
+			buf.WriteString("</blockquote>")
+
+		case reListItem.MatchString(line):
+			var list string
+			list, i = convertList(lines, i, opts)
+			buf.WriteString(list)
+
+		default:
+			buf.WriteString(convertInline(line, opts))
+			buf.WriteString("\n")
+			i++
+		}
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// closesFence reports whether line closes a code block that was
+// opened with fence, which takes at least as many backticks.
+func closesFence(line, fence string) bool {
+	line = strings.TrimSpace(line)
+	return len(line) >= len(fence) && strings.Trim(line, "`") == ""
+}
+
+func isOrderedMarker(marker string) bool {
+	return marker != "-" && marker != "*" && marker != "+"
+}
+
+// convertList converts the list that starts at lines[i] including
+// any lists nested within it and returns the index of the first
+// line after the list.
+func convertList(lines []string, i int, opts *Options) (string, int) {
+	first := reListItem.FindStringSubmatch(lines[i])
+	indent := len(first[1])
+	ordered := isOrderedMarker(first[2])
+
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("<" + tag + ">")
+	for i < len(lines) {
+		matches := reListItem.FindStringSubmatch(lines[i])
+		if matches == nil || len(matches[1]) < indent {
+			break
+		}
+		if len(matches[1]) == indent && isOrderedMarker(matches[2]) != ordered {
+			break
+		}
+
+		buf.WriteString("<li>")
+		buf.WriteString(convertInline(matches[3], opts))
+		i++
+		for i < len(lines) {
+			nested := reListItem.FindStringSubmatch(lines[i])
+			if nested == nil || len(nested[1]) <= indent {
+				break
+			}
+			var sublist string
+			sublist, i = convertList(lines, i, opts)
+			buf.WriteString(sublist)
+		}
+		buf.WriteString("</li>")
+	}
+	buf.WriteString("</" + tag + ">")
+	return buf.String(), i
+}
+
+func convertInline(text string, opts *Options) string {
+	return convertInlineIn(text, opts, false)
+}
+
+// convertInlineIn converts text which is the label of a link if
+// inLink is set. Links cannot contain other links or mentions
+// so those are left as text within a label.
+func convertInlineIn(text string, opts *Options, inLink bool) string {
+	buf := new(bytes.Buffer)
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch {
+		case rest[0] == '\\' && len(rest) > 1:
+			buf.WriteString(escape(rest[1:2]))
+			i += 2
+			continue
+
+		case rest[0] == '`':
+			n := runLength(text, i)
+			if end := codeSpanEnd(text, i+n, n); end >= 0 {
+				buf.WriteString("<code>" + escape(trimCodeSpan(text[i+n:end])) + "</code>")
+				i = end + n
+				continue
+			}
+			// A run of backticks that is never closed is literal.
+			buf.WriteString(rest[:n])
+			i += n
+			continue
+
+		case rest[0] == '~':
+			n := runLength(text, i)
+			if n == 2 {
+				if end := closingDelimiter(text, i, n); end > 0 {
+					buf.WriteString("<s>" + convertInlineIn(text[i+n:end], opts, inLink) + "</s>")
+					i = end + n
+					continue
+				}
+			}
+			buf.WriteString(rest[:n])
+			i += n
+			continue
+
+		case rest[0] == '*', rest[0] == '_' && atWordBoundary(text, i):
+			n := runLength(text, i)
+			if k, end := matchEmphasis(text, i, n); end > 0 {
+				inner := convertInlineIn(text[i+k:end], opts, inLink)
+				switch k {
+				case 1:
+					buf.WriteString("<em>" + inner + "</em>")
+				case 2:
+					buf.WriteString("<strong>" + inner + "</strong>")
+				default:
+					buf.WriteString("<em><strong>" + inner + "</strong></em>")
+				}
+				i = end + k
+				continue
+			}
+			buf.WriteString(rest[:n])
+			i += n
+			continue
+
+		case rest[0] == '_':
+			// Underscores within words such as in snake_case are literal.
+			n := runLength(text, i)
+			buf.WriteString(rest[:n])
+			i += n
+			continue
+
+		case rest[0] == '[' && !inLink:
+			if label, href, n, ok := parseLink(rest); ok {
+				if matches := reAsanaTask.FindStringSubmatch(href); matches != nil {
+					buf.WriteString(objectReference(matches[1]))
+				} else {
+					buf.WriteString(`<a href="` + escape(href) + `">` + convertInlineIn(label, opts, true) + "</a>")
+				}
+				i += n
+				continue
+			}
+
+		case rest[0] == '@' && !inLink && atWordBoundary(text, i):
+			handle := reMentionName.FindString(rest[1:])
+			// Trailing punctuation such as in "thanks @odeke." is not part of the handle.
+			handle = strings.TrimRight(handle, ".-")
+			if gid, ok := opts.mentionGID(handle); ok && handle != "" {
+				buf.WriteString(objectReference(gid))
+				i += len(handle) + 1
+				continue
+			}
+		}
+
+		buf.WriteString(escape(rest[:1]))
+		i++
+	}
+	return buf.String()
+}
+
+func objectReference(gid string) string {
+	return `<a data-asana-gid="` + escape(gid) + `"/>`
+}
+
+// atWordBoundary reports whether text[i] is not preceded by a letter or digit.
+func atWordBoundary(text string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev := rune(text[i-1])
+	return !unicode.IsLetter(prev) && !unicode.IsDigit(prev)
+}
+
+// runLength returns the number of times that text[i] repeats from i on.
+func runLength(text string, i int) int {
+	n := 1
+	for i+n < len(text) && text[i+n] == text[i] {
+		n++
+	}
+	return n
+}
+
+// codeSpanEnd returns the index of the run of exactly n backticks
+// that closes a code span whose content starts at text[start],
+// or -1 if it is never closed. Backslashes are literal in code.
+func codeSpanEnd(text string, start, n int) int {
+	for j := start; j < len(text); {
+		if text[j] != '`' {
+			j++
+			continue
+		}
+		m := runLength(text, j)
+		if m == n {
+			return j
+		}
+		j += m
+	}
+	return -1
+}
+
+// trimCodeSpan strips the space that separates the content
+// of a code span from delimiters that it would run into.
+func trimCodeSpan(code string) string {
+	if len(code) >= 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+		return code[1 : len(code)-1]
+	}
+	return code
+}
+
+// skipEscapeOrCode returns the index after the backslash escape
+// or code span at text[j], or -1 if there is neither.
+func skipEscapeOrCode(text string, j int) int {
+	switch text[j] {
+	case '\\':
+		return j + 2
+	case '`':
+		m := runLength(text, j)
+		if end := codeSpanEnd(text, j+m, m); end >= 0 {
+			return end + m
+		}
+		return j + m
+	}
+	return -1
+}
+
+// matchEmphasis finds the delimiters that close the run of n '*' or
+// '_' at text[start]. It returns how many of them are used and the
+// index of the closing run or -1 if the run is never closed. A run of
+// three is tried as a whole first, then as emphasis around a run of two.
+func matchEmphasis(text string, start, n int) (k, end int) {
+	candidates := []int{n}
+	switch {
+	case n == 3:
+		candidates = []int{3, 1, 2}
+	case n > 3:
+		return 0, -1
+	}
+	for _, k := range candidates {
+		if end := closingDelimiter(text, start, k); end > 0 {
+			return k, end
+		}
+	}
+	return 0, -1
+}
+
+// closingDelimiter returns the index of the run of exactly n delimiters
+// that closes the one opened at text[start], or -1 if it is never closed.
+// Escaped delimiters and those in code spans are skipped.
+func closingDelimiter(text string, start, n int) int {
+	delim := text[start]
+	for j := start + n; j < len(text); {
+		if next := skipEscapeOrCode(text, j); next >= 0 {
+			j = next
+			continue
+		}
+		if text[j] != delim {
+			j++
+			continue
+		}
+		m := runLength(text, j)
+		// An underscore only closes at the end of a word.
+		closesWord := delim != '_' || j+m == len(text) || atWordBoundary(text, j+m+1)
+		if m == n && j > start+n && closesWord {
+			return j
+		}
+		j += m
+	}
+	return -1
+}
+
+// parseLink parses "[label](href)" at the start of text and returns
+// the number of bytes it spans. Brackets in the label and parentheses
+// in the href may be nested or escaped with a backslash.
+func parseLink(text string) (label, href string, n int, ok bool) {
+	closeLabel := -1
+	depth := 0
+scan:
+	for j := 1; j < len(text); {
+		if next := skipEscapeOrCode(text, j); next >= 0 {
+			j = next
+			continue
+		}
+		switch text[j] {
+		case '[':
+			depth++
+		case ']':
+			if depth == 0 {
+				closeLabel = j
+				break scan
+			}
+			depth--
+		}
+		j++
+	}
+	if closeLabel < 0 || !strings.HasPrefix(text[closeLabel+1:], "(") {
+		return "", "", 0, false
+	}
+
+	hrefBuf := new(bytes.Buffer)
+	depth = 0
+	for j := closeLabel + 2; j < len(text); j++ {
+		c := text[j]
+		switch {
+		case c == '\\' && j+1 < len(text) && isASCIIPunct(text[j+1]):
+			j++
+			c = text[j]
+		case c == '(':
+			depth++
+		case c == ')' && depth == 0:
+			href = strings.TrimSpace(hrefBuf.String())
+			if href == "" {
+				return "", "", 0, false
+			}
+			return text[1:closeLabel], href, j + 1, true
+		case c == ')':
+			depth--
+		}
+		hrefBuf.WriteByte(c)
+	}
+	return "", "", 0, false
+}
+
+func isASCIIPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+func isAllDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+var escaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&quot;",
+)
+
+// escape unlike xml.EscapeText, leaves newlines
+// intact since Asana preserves them in text.
+func escape(s string) string {
+	return escaper.Replace(s)
+}
@@ -0,0 +1,166 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package richtext converts between Markdown and the restricted
+// XML subset that Asana accepts in fields such as "html_notes"
+// and "html_text", for example:
+//
+//	<body>Deploy <strong>v1.2</strong> for <a data-asana-gid="1234"/></body>
+package richtext
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// allowedAttrs maps each tag that Asana supports
+// to the attributes that it may carry.
+var allowedAttrs = map[string]map[string]bool{
+	"body":       nil,
+	"strong":     nil,
+	"em":         nil,
+	"u":          nil,
+	"s":          nil,
+	"code":       nil,
+	"pre":        nil,
+	"ol":         nil,
+	"ul":         nil,
+	"li":         nil,
+	"blockquote": nil,
+	"h1":         nil,
+	"h2":         nil,
+	"hr":         nil,
+	"a": {
+		"href":               true,
+		"data-asana-gid":     true,
+		"data-asana-type":    true,
+		"data-asana-dynamic": true,
+	},
+}
+
+var (
+	errEmptyDocument = errors.New("expecting a non-empty document")
+	errNoBody        = errors.New("expecting the document to be wrapped in <body></body>")
+	errNestedLink    = errors.New("links cannot contain other links or mentions")
+)
+
+// UnsupportedTagError is returned when a document
+// contains a tag that Asana does not accept.
+type UnsupportedTagError struct {
+	Tag string
+}
+
+func (ute *UnsupportedTagError) Error() string {
+	return fmt.Sprintf("unsupported tag <%s>", ute.Tag)
+}
+
+// UnsupportedAttrError is returned when a supported
+// tag carries an attribute that Asana does not accept.
+type UnsupportedAttrError struct {
+	Tag  string
+	Attr string
+}
+
+func (uae *UnsupportedAttrError) Error() string {
+	return fmt.Sprintf("unsupported attribute %q on <%s>", uae.Attr, uae.Tag)
+}
+
+// Validate checks that doc is well formed, is wrapped in
+// a single <body> element and only uses the tags and
+// attributes that Asana supports in rich text.
+func Validate(doc string) error {
+	_, err := parse(doc)
+	return err
+}
+
+// node is an element or, if name is blank, a text node.
+type node struct {
+	name     string
+	attrs    map[string]string
+	text     string
+	children []*node
+}
+
+func parse(doc string) (*node, error) {
+	if strings.TrimSpace(doc) == "" {
+		return nil, errEmptyDocument
+	}
+
+	dec := xml.NewDecoder(strings.NewReader(doc))
+	root := &node{}
+	stack := []*node{root}
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		parent := stack[len(stack)-1]
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := t.Name.Local
+			allowed, known := allowedAttrs[name]
+			if !known {
+				return nil, &UnsupportedTagError{Tag: name}
+			}
+			if (name == "body") != (len(stack) == 1) {
+				return nil, errNoBody
+			}
+			if name == "a" && insideLink(stack) {
+				return nil, errNestedLink
+			}
+			n := &node{name: name, attrs: make(map[string]string)}
+			for _, attr := range t.Attr {
+				if !allowed[attr.Name.Local] {
+					return nil, &UnsupportedAttrError{Tag: name, Attr: attr.Name.Local}
+				}
+				n.attrs[attr.Name.Local] = attr.Value
+			}
+			parent.children = append(parent.children, n)
+			stack = append(stack, n)
+
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+
+		case xml.CharData:
+			if len(stack) == 1 {
+				if strings.TrimSpace(string(t)) != "" {
+					return nil, errNoBody
+				}
+				continue
+			}
+			parent.children = append(parent.children, &node{text: string(t)})
+		}
+	}
+
+	if len(root.children) != 1 {
+		return nil, errNoBody
+	}
+	return root.children[0], nil
+}
+
+func insideLink(stack []*node) bool {
+	for _, n := range stack {
+		if n.name == "a" {
+			return true
+		}
+	}
+	return false
+}
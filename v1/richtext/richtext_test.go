@@ -0,0 +1,215 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package richtext_test
+
+import (
+	"testing"
+
+	"github.com/orijtech/asana/v1/richtext"
+)
+
+func TestFromMarkdown(t *testing.T) {
+	opts := &richtext.Options{
+		Mentions: map[string]string{"odeke": "12345"},
+	}
+
+	tests := [...]struct {
+		md   string
+		want string
+	}{
+		0: {
+			md:   "Deploy **v1.2** to *staging* with `make deploy`",
+			want: "<body>Deploy <strong>v1.2</strong> to <em>staging</em> with <code>make deploy</code></body>",
+		},
+		1: {
+			md:   "Ping @odeke and @67890, not me@example.com or @unknown.",
+			want: `<body>Ping <a data-asana-gid="12345"/> and <a data-asana-gid="67890"/>, not me@example.com or @unknown.</body>`,
+		},
+		2: {
+			md:   "Blocked by [the migration](https://app.asana.com/0/111/222) see [docs](https://example.com/a?b=1&c=2)",
+			want: `<body>Blocked by <a data-asana-gid="222"/> see <a href="https://example.com/a?b=1&amp;c=2">docs</a></body>`,
+		},
+		3: {
+			md:   "# Release\n## Steps\n1. Build\n2. Ship\n   - canary\n   - rollout\n\nDone",
+			want: "<body><h1>Release</h1><h2>Steps</h2><ol><li>Build</li><li>Ship<ul><li>canary</li><li>rollout</li></ul></li></ol>\nDone</body>",
+		},
+		4: {
+			md:   "> quoted **text**\n> second line\n---\n```\nif a < b {\n}\n```",
+			want: "<body><blockquote>quoted <strong>text</strong>\nsecond line</blockquote><hr/><pre>if a &lt; b {\n}</pre></body>",
+		},
+		5: {
+			md:   "snake_case_name stays, ~~old~~ and \\*literal\\*",
+			want: "<body>snake_case_name stays, <s>old</s> and *literal*</body>",
+		},
+		6: {
+			md:   "### Deep\n#### Deeper",
+			want: "<body><h2>Deep</h2><h2>Deeper</h2></body>",
+		},
+		7: {
+			md:   "*a **b** c* and ***d** e*",
+			want: "<body><em>a <strong>b</strong> c</em> and <em><strong>d</strong> e</em></body>",
+		},
+		8: {
+			md:   "`` a`b `` and *not \\*closed*",
+			want: "<body><code>a`b</code> and <em>not *closed</em></body>",
+		},
+		9: {
+			md:   "[x](http://x.com/a_(b)) and [ping @odeke](https://example.com)",
+			want: `<body><a href="http://x.com/a_(b)">x</a> and <a href="https://example.com">ping @odeke</a></body>`,
+		},
+		10: {
+			md:   "````\n```\nnested\n```\n````",
+			want: "<body><pre>```\nnested\n```</pre></body>",
+		},
+	}
+
+	for i, tt := range tests {
+		got, err := richtext.FromMarkdown(tt.md, opts)
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("#%d:\ngot:  %q\nwant: %q", i, got, tt.want)
+		}
+	}
+}
+
+func TestToMarkdown(t *testing.T) {
+	tests := [...]struct {
+		doc     string
+		want    string
+		wantErr bool
+	}{
+		0: {
+			doc:  "<body>Deploy <strong>v1.2</strong> for <a data-asana-gid=\"12345\"/> see <a href=\"https://example.com\">docs</a></body>",
+			want: "Deploy **v1.2** for @12345 see [docs](https://example.com)",
+		},
+		1: {
+			doc:  "<body>Steps\n<ol><li>Build</li><li>Ship<ul><li>canary</li></ul></li></ol>\nDone <u>now</u></body>",
+			want: "Steps\n1. Build\n2. Ship\n  - canary\n\nDone now",
+		},
+		2: {
+			doc:  "<body><h1>Title</h1><blockquote>a\nb</blockquote><pre>x := 2 * 3</pre></body>",
+			want: "# Title\n> a\n> b\n```\nx := 2 * 3\n```",
+		},
+		3: {
+			doc:     "<body><script>alert(1)</script></body>",
+			wantErr: true,
+		},
+		4: {
+			doc:     "<body><a onclick=\"x()\">hi</a></body>",
+			wantErr: true,
+		},
+		5: {
+			doc:     "no body here",
+			wantErr: true,
+		},
+		6: {
+			doc:     "<body><strong>unclosed</body>",
+			wantErr: true,
+		},
+		7: {
+			doc:     "<body><a href=\"https://example.com\">by <a data-asana-gid=\"12345\"/></a></body>",
+			wantErr: true,
+		},
+	}
+
+	for i, tt := range tests {
+		got, err := richtext.ToMarkdown(tt.doc)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("#%d:\ngot:  %q\nwant: %q", i, got, tt.want)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	mds := []string{
+		"Deploy **v1.2** to *staging* for @12345",
+		"# Release\n- a\n  - b\n- c\n\nOutro with `code`",
+		"> quote\n\n[link](https://example.com)",
+	}
+
+	for i, md := range mds {
+		doc, err := richtext.FromMarkdown(md, nil)
+		if err != nil {
+			t.Errorf("#%d: FromMarkdown err: %v", i, err)
+			continue
+		}
+		got, err := richtext.ToMarkdown(doc)
+		if err != nil {
+			t.Errorf("#%d: ToMarkdown err: %v", i, err)
+			continue
+		}
+		if got != md {
+			t.Errorf("#%d:\ngot:  %q\nwant: %q", i, got, md)
+		}
+	}
+}
+
+func TestRichTextRoundTrip(t *testing.T) {
+	docs := []string{
+		"<body>snake_case_name and __init__ stay as they are</body>",
+		"<body>#hashtag\n# not a heading\n&gt; not a quote, 2 &gt; 1</body>",
+		"<body>- not a list\n+ nor this\n  * nor this\n1. nor this\n2) nor this\n---</body>",
+		"<body>email me@example.com or @12345, not a mention</body>",
+		"<body><h2>Steps</h2><ol><li>1. Build</li><li>- Ship</li></ol></body>",
+		"<body><em>a <strong>b</strong> c</em></body>",
+		"<body><em>a*b</em> and <strong>c**d</strong> and <s>e~~f</s></body>",
+		"<body><code>a`b</code>, <code>`x</code> and <code> y </code></body>",
+		`<body><a href="http://x.com/a_(b)">x</a> and <a href="http://x.com/(c\\d">a [b] c) d</a></body>`,
+		"<body><pre>```\ncode\n```</pre></body>",
+	}
+
+	for i, doc := range docs {
+		md, err := richtext.ToMarkdown(doc)
+		if err != nil {
+			t.Errorf("#%d: ToMarkdown err: %v", i, err)
+			continue
+		}
+		got, err := richtext.FromMarkdown(md, nil)
+		if err != nil {
+			t.Errorf("#%d: FromMarkdown err: %v", i, err)
+			continue
+		}
+		if got != doc {
+			t.Errorf("#%d: via %q\ngot:  %q\nwant: %q", i, md, got, doc)
+		}
+	}
+
+	// Headings deeper than "##" are clamped to <h2>
+	// so they come back as "##" headings.
+	doc, err := richtext.FromMarkdown("### Deep", nil)
+	if err != nil {
+		t.Fatalf("FromMarkdown err: %v", err)
+	}
+	md, err := richtext.ToMarkdown(doc)
+	if err != nil {
+		t.Fatalf("ToMarkdown err: %v", err)
+	}
+	if want := "## Deep"; md != want {
+		t.Errorf("got=%q want=%q", md, want)
+	}
+}
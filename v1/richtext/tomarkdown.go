@@ -0,0 +1,233 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package richtext
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ToMarkdown converts a document in Asana's rich text format into
+// Markdown. References to other Asana objects such as user mentions
+// and task links are rendered as @gid, which FromMarkdown converts back.
+func ToMarkdown(doc string) (string, error) {
+	body, err := parse(doc)
+	if err != nil {
+		return "", err
+	}
+	buf := new(bytes.Buffer)
+	renderBlocks(buf, body.children, 0)
+	return strings.TrimRight(buf.String(), "\n"), nil
+}
+
+var blockTags = map[string]bool{
+	"ul":         true,
+	"ol":         true,
+	"blockquote": true,
+	"pre":        true,
+	"h1":         true,
+	"h2":         true,
+	"hr":         true,
+}
+
+// startBlock ensures that a block element starts on its own line.
+func startBlock(buf *bytes.Buffer) {
+	if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteString("\n")
+	}
+}
+
+func renderBlocks(buf *bytes.Buffer, nodes []*node, depth int) {
+	for _, n := range nodes {
+		if !blockTags[n.name] {
+			renderInline(buf, n)
+			continue
+		}
+
+		startBlock(buf)
+		switch n.name {
+		case "ul", "ol":
+			renderList(buf, n, depth)
+
+		case "blockquote":
+			inner := new(bytes.Buffer)
+			renderBlocks(inner, n.children, 0)
+			for _, line := range strings.Split(strings.TrimRight(inner.String(), "\n"), "\n") {
+				buf.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+			}
+
+		case "pre":
+			code := textContent(n)
+			// The fence must be longer than any run of backticks in the code.
+			fence := strings.Repeat("`", longestRun(code, '`')+1)
+			if len(fence) < 3 {
+				fence = "```"
+			}
+			buf.WriteString(fence + "\n" + code + "\n" + fence + "\n")
+
+		case "h1", "h2":
+			marker := "#"
+			if n.name == "h2" {
+				marker = "##"
+			}
+			buf.WriteString(marker + " ")
+			renderInlineChildren(buf, n)
+			buf.WriteString("\n")
+
+		case "hr":
+			buf.WriteString("---\n")
+		}
+	}
+}
+
+func renderList(buf *bytes.Buffer, list *node, depth int) {
+	indent := strings.Repeat("  ", depth)
+	index := 0
+	for _, item := range list.children {
+		if item.name != "li" {
+			continue
+		}
+		index++
+		marker := "-"
+		if list.name == "ol" {
+			marker = strconv.Itoa(index) + "."
+		}
+		buf.WriteString(indent + marker + " ")
+		for _, child := range item.children {
+			if child.name == "ul" || child.name == "ol" {
+				startBlock(buf)
+				renderList(buf, child, depth+1)
+				continue
+			}
+			renderInline(buf, child)
+		}
+		startBlock(buf)
+	}
+}
+
+func renderInlineChildren(buf *bytes.Buffer, n *node) {
+	for _, child := range n.children {
+		renderInline(buf, child)
+	}
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"`", "\\`",
+	"[", `\[`,
+	"]", `\]`,
+	"~", `\~`,
+	"#", `\#`,
+	">", `\>`,
+	"@", `\@`,
+)
+
+// reLineStartMarker matches text at the start of a line that
+// FromMarkdown would otherwise read as a list item or a rule.
+var reLineStartMarker = regexp.MustCompile(`^\s*(?:[-+]|\d+[.)])`)
+
+// escapeText writes text so that FromMarkdown reads it back as plain text.
+func escapeText(buf *bytes.Buffer, text string) {
+	for i, line := range strings.Split(text, "\n") {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		line = markdownEscaper.Replace(line)
+		atLineStart := buf.Len() == 0 || bytes.HasSuffix(buf.Bytes(), []byte("\n"))
+		if loc := reLineStartMarker.FindStringIndex(line); atLineStart && loc != nil {
+			// Escape the last character of the marker.
+			at := loc[1] - 1
+			line = line[:at] + `\` + line[at:]
+		}
+		buf.WriteString(line)
+	}
+}
+
+var hrefEscaper = strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`)
+
+// codeSpan delimits code with more backticks than it contains
+// and pads it with spaces if it starts or ends with a backtick.
+func codeSpan(code string) string {
+	delim := strings.Repeat("`", longestRun(code, '`')+1)
+	pad := ""
+	if code != "" && (code[0] == '`' || code[len(code)-1] == '`' || trimCodeSpan(code) != code) {
+		pad = " "
+	}
+	return delim + pad + code + pad + delim
+}
+
+// longestRun returns the length of the longest run of c in s.
+func longestRun(s string, c byte) int {
+	longest, n := 0, 0
+	for i := 0; i < len(s); i++ {
+		if s[i] != c {
+			n = 0
+			continue
+		}
+		n++
+		if n > longest {
+			longest = n
+		}
+	}
+	return longest
+}
+
+func renderInline(buf *bytes.Buffer, n *node) {
+	wrap := func(delim string) {
+		buf.WriteString(delim)
+		renderInlineChildren(buf, n)
+		buf.WriteString(delim)
+	}
+
+	switch n.name {
+	case "":
+		escapeText(buf, n.text)
+	case "strong":
+		wrap("**")
+	case "em":
+		wrap("*")
+	case "s":
+		wrap("~~")
+	case "code":
+		buf.WriteString(codeSpan(textContent(n)))
+	case "a":
+		if gid := n.attrs["data-asana-gid"]; gid != "" {
+			buf.WriteString("@" + gid)
+			return
+		}
+		buf.WriteString("[")
+		renderInlineChildren(buf, n)
+		buf.WriteString("](" + hrefEscaper.Replace(n.attrs["href"]) + ")")
+	default:
+		// Tags such as <u> have no Markdown
+		// equivalent so only their text is kept.
+		renderInlineChildren(buf, n)
+	}
+}
+
+func textContent(n *node) string {
+	if n.name == "" {
+		return n.text
+	}
+	var parts []string
+	for _, child := range n.children {
+		parts = append(parts, textContent(child))
+	}
+	return strings.Join(parts, "")
+}
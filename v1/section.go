@@ -0,0 +1,282 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/orijtech/otils"
+)
+
+// Section is a subdivision of a project. In projects
+// with a BoardLayout, sections are shown as columns.
+type Section struct {
+	ID        int64              `json:"id,omitempty"`
+	GID       string             `json:"gid,omitempty"`
+	Name      string             `json:"name,omitempty"`
+	CreatedAt *time.Time         `json:"created_at,omitempty"`
+	Project   *NamedAndIDdEntity `json:"project,omitempty"`
+}
+
+type SectionRequest struct {
+	// SectionID is the gid of the section to update.
+	SectionID string `json:"-"`
+
+	// ProjectID is the gid of the project to create the section in.
+	ProjectID string `json:"-"`
+
+	Name string `json:"name,omitempty"`
+
+	// InsertBefore and InsertAfter are the gids of the sections
+	// to place a new section before or after. Only one can be set.
+	InsertBefore string `json:"insert_before,omitempty"`
+	InsertAfter  string `json:"insert_after,omitempty"`
+}
+
+var (
+	errNilSectionRequest = errors.New("expecting a non-nil sectionRequest")
+	errEmptySectionID    = errors.New("expecting a non-empty sectionID")
+	errEmptySectionName  = errors.New("expecting a non-empty section name")
+	errNilSectionInsert  = errors.New("expecting a non-nil sectionInsert")
+	errNilTaskInsert     = errors.New("expecting a non-nil sectionTaskInsert")
+	errInsertUnanchored  = errors.New("expecting one of before or after to be set")
+)
+
+func (sreq *SectionRequest) Validate() error {
+	if sreq == nil {
+		return errNilSectionRequest
+	}
+	if strings.TrimSpace(sreq.ProjectID) == "" {
+		return errEmptyProjectID
+	}
+	if strings.TrimSpace(sreq.Name) == "" {
+		return errEmptySectionName
+	}
+	if sreq.InsertBefore != "" && sreq.InsertAfter != "" {
+		return errBothBeforeAndAfter
+	}
+	return nil
+}
+
+type sectionWrap struct {
+	Section *Section `json:"data"`
+}
+
+func parseOutSectionFromData(blob []byte) (*Section, error) {
+	sw := new(sectionWrap)
+	if err := json.Unmarshal(blob, sw); err != nil {
+		return nil, err
+	}
+	return sw.Section, nil
+}
+
+func (c *Client) CreateSection(sreq *SectionRequest) (*Section, error) {
+	if err := sreq.Validate(); err != nil {
+		return nil, err
+	}
+	qs, err := otils.ToURLValues(sreq)
+	if err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/projects/%s/sections", strings.TrimSpace(sreq.ProjectID))
+	slurp, err := c.doFormReq("POST", path, qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutSectionFromData(slurp)
+}
+
+// UpdateSection renames a section. Use InsertSection to reorder it.
+func (c *Client) UpdateSection(sreq *SectionRequest) (*Section, error) {
+	if sreq == nil {
+		return nil, errNilSectionRequest
+	}
+	sectionID := strings.TrimSpace(sreq.SectionID)
+	if sectionID == "" {
+		return nil, errEmptySectionID
+	}
+	if strings.TrimSpace(sreq.Name) == "" {
+		return nil, errEmptySectionName
+	}
+
+	copyReq := *sreq
+	copyReq.InsertBefore, copyReq.InsertAfter = "", ""
+	qs, err := otils.ToURLValues(&copyReq)
+	if err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/sections/%s", sectionID)
+	slurp, err := c.doFormReq("PUT", path, qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutSectionFromData(slurp)
+}
+
+func (c *Client) FindSectionByID(sectionID string) (*Section, error) {
+	sectionID = strings.TrimSpace(sectionID)
+	if sectionID == "" {
+		return nil, errEmptySectionID
+	}
+	fullURL := fmt.Sprintf("%s/sections/%s", baseURL, sectionID)
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutSectionFromData(slurp)
+}
+
+// DeleteSection deletes a section. Asana only allows
+// deleting sections that no longer contain any tasks.
+func (c *Client) DeleteSection(sectionID string) error {
+	sectionID = strings.TrimSpace(sectionID)
+	if sectionID == "" {
+		return errEmptySectionID
+	}
+	fullURL := fmt.Sprintf("%s/sections/%s", baseURL, sectionID)
+	req, err := http.NewRequest("DELETE", fullURL, nil)
+	if err != nil {
+		return err
+	}
+	_, _, err = c.doAuthReqThenSlurpBody(req)
+	return err
+}
+
+type SectionInsert struct {
+	// SectionID is the gid of the section to move.
+	SectionID string `json:"section"`
+
+	// Only one of BeforeSectionID or AfterSectionID can be set.
+	BeforeSectionID string `json:"before_section,omitempty"`
+	AfterSectionID  string `json:"after_section,omitempty"`
+}
+
+func (si *SectionInsert) Validate() error {
+	if si == nil {
+		return errNilSectionInsert
+	}
+	if strings.TrimSpace(si.SectionID) == "" {
+		return errEmptySectionID
+	}
+	if si.BeforeSectionID != "" && si.AfterSectionID != "" {
+		return errBothBeforeAndAfter
+	}
+	if si.BeforeSectionID == "" && si.AfterSectionID == "" {
+		return errInsertUnanchored
+	}
+	return nil
+}
+
+// InsertSection moves a section of a project to
+// before or after another section of that project.
+func (c *Client) InsertSection(projectID string, si *SectionInsert) error {
+	projectID = strings.TrimSpace(projectID)
+	if projectID == "" {
+		return errEmptyProjectID
+	}
+	if err := si.Validate(); err != nil {
+		return err
+	}
+	qs, err := otils.ToURLValues(si)
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/projects/%s/sections/insert", projectID)
+	_, err = c.doFormReq("POST", path, qs)
+	return err
+}
+
+type SectionTaskInsert struct {
+	TaskID string `json:"task"`
+
+	// InsertBefore and InsertAfter are the gids of tasks in the
+	// section to place the task before or after. If neither is
+	// set, the task is added to the end of the section.
+	InsertBefore string `json:"insert_before,omitempty"`
+	InsertAfter  string `json:"insert_after,omitempty"`
+}
+
+// AddTaskToSection moves a task into a section, removing it from any
+// other section of the project that the section belongs to.
+func (c *Client) AddTaskToSection(sectionID string, sti *SectionTaskInsert) error {
+	sectionID = strings.TrimSpace(sectionID)
+	if sectionID == "" {
+		return errEmptySectionID
+	}
+	if sti == nil {
+		return errNilTaskInsert
+	}
+	if strings.TrimSpace(sti.TaskID) == "" {
+		return errEmptyTaskID
+	}
+	if sti.InsertBefore != "" && sti.InsertAfter != "" {
+		return errBothBeforeAndAfter
+	}
+	qs, err := otils.ToURLValues(sti)
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/sections/%s/addTask", sectionID)
+	_, err = c.doFormReq("POST", path, qs)
+	return err
+}
+
+func (c *Client) TasksForSection(sectionID string) (resultsChan chan *TaskResultPage, cancelChan chan<- bool, err error) {
+	sectionID = strings.TrimSpace(sectionID)
+	if sectionID == "" {
+		return nil, nil, errEmptySectionID
+	}
+
+	startPath := fmt.Sprintf("/sections/%s/tasks?limit=%d", sectionID, defaultTaskLimit)
+	return c.doTasksPaging(startPath)
+}
+
+type SectionsPage struct {
+	Sections []*Section `json:"data"`
+	Err      error
+}
+
+type sectionsPager struct {
+	SectionsPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+func (c *Client) ListSectionsForProject(projectID string) (pagesChan chan *SectionsPage, cancelChan chan<- bool, err error) {
+	projectID = strings.TrimSpace(projectID)
+	if projectID == "" {
+		return nil, nil, errEmptyProjectID
+	}
+
+	pagesChan = make(chan *SectionsPage)
+	path := fmt.Sprintf("/projects/%s/sections", projectID)
+	cancel := c.sendPages(pagesChan, path, func(slurp []byte) (interface{}, string, error) {
+		pager := new(sectionsPager)
+		if err := json.Unmarshal(slurp, pager); err != nil {
+			return nil, "", err
+		}
+		return &pager.SectionsPage, pager.NextPage.nextPath(), nil
+	})
+	return pagesChan, cancel, nil
+}
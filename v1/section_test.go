@@ -0,0 +1,118 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestListSectionsForProject(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: sectionsRoute})
+
+	if _, _, err := client.ListSectionsForProject("  "); err == nil {
+		t.Errorf("expected an error for a blank projectID")
+	}
+
+	pagesChan, _, err := client.ListSectionsForProject(projectID1)
+	if err != nil {
+		t.Fatalf("listing sections: %v", err)
+	}
+
+	var gotNames []string
+	for page := range pagesChan {
+		if err := page.Err; err != nil {
+			t.Errorf("page err: %v", err)
+			continue
+		}
+		for _, section := range page.Sections {
+			gotNames = append(gotNames, section.Name)
+		}
+	}
+
+	wantNames := []string{"To do", "In progress", "Deployed"}
+	if !reflect.DeepEqual(gotNames, wantNames) {
+		t.Errorf("gotNames=%v wantNames=%v", gotNames, wantNames)
+	}
+}
+
+func TestAddTaskToSection(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: sectionsRoute})
+
+	tests := [...]struct {
+		sectionID string
+		insert    *asana.SectionTaskInsert
+		wantErr   bool
+	}{
+		0: {sectionID: "302", insert: &asana.SectionTaskInsert{TaskID: "1001", InsertAfter: "1002"}},
+		1: {sectionID: "", insert: &asana.SectionTaskInsert{TaskID: "1001"}, wantErr: true},
+		2: {sectionID: "302", insert: nil, wantErr: true},
+		3: {sectionID: "302", insert: &asana.SectionTaskInsert{TaskID: "1001", InsertBefore: "1", InsertAfter: "2"}, wantErr: true},
+		4: {sectionID: "302", insert: &asana.SectionTaskInsert{TaskID: "1001"}, wantErr: true},
+	}
+
+	for i, tt := range tests {
+		err := client.AddTaskToSection(tt.sectionID, tt.insert)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+		}
+	}
+}
+
+func (b *backend) sectionsRoundTrip(req *http.Request) (*http.Response, error) {
+	switch {
+	case strings.HasSuffix(req.URL.Path, "/projects/"+projectID1+"/sections"):
+		if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		if req.URL.Query().Get("offset") == "page-2" {
+			return makeRespFromFile("./testdata/sections-project-1-page-2.json")
+		}
+		return makeRespFromFile("./testdata/sections-project-1-page-1.json")
+
+	case strings.HasSuffix(req.URL.Path, "/sections/302/addTask"):
+		if badAuthResp, err := b.checkAuthorization(req, "POST"); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		if err := req.ParseForm(); err != nil {
+			return makeResp(err.Error(), http.StatusBadRequest, nil), nil
+		}
+		if req.PostForm.Get("task") == "" || req.PostForm.Get("insert_after") != "1002" {
+			return makeResp("expecting the task to be inserted after 1002", http.StatusBadRequest, nil), nil
+		}
+		return makeResp("200 OK", http.StatusOK, nopCloser("{\"data\": {}}")), nil
+
+	default:
+		return unknownRouteResp, nil
+	}
+}
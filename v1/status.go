@@ -0,0 +1,215 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/orijtech/otils"
+)
+
+type StatusType string
+
+const (
+	// The statuses of projects and portfolios.
+	StatusOnTrack  StatusType = "on_track"
+	StatusAtRisk   StatusType = "at_risk"
+	StatusOffTrack StatusType = "off_track"
+	StatusOnHold   StatusType = "on_hold"
+	StatusComplete StatusType = "complete"
+
+	// The statuses of goals.
+	StatusAchieved StatusType = "achieved"
+	StatusPartial  StatusType = "partial"
+	StatusMissed   StatusType = "missed"
+	StatusDropped  StatusType = "dropped"
+)
+
+var knownStatusTypes = map[StatusType]bool{
+	StatusOnTrack:  true,
+	StatusAtRisk:   true,
+	StatusOffTrack: true,
+	StatusOnHold:   true,
+	StatusComplete: true,
+	StatusAchieved: true,
+	StatusPartial:  true,
+	StatusMissed:   true,
+	StatusDropped:  true,
+}
+
+func (st StatusType) IsValid() bool {
+	return knownStatusTypes[st]
+}
+
+type StatusUpdate struct {
+	ID  int64  `json:"id,omitempty"`
+	GID string `json:"gid,omitempty"`
+
+	StatusType StatusType `json:"status_type,omitempty"`
+
+	// Color is only set on legacy project statuses, such
+	// as Project.CurrentStatus, and is one of "green",
+	// "yellow", "red" or "blue".
+	Color string `json:"color,omitempty"`
+
+	Title    string `json:"title,omitempty"`
+	Text     string `json:"text,omitempty"`
+	HTMLText string `json:"html_text,omitempty"`
+
+	// Parent is the project, portfolio or
+	// goal that the status update is about.
+	Parent *NamedAndIDdEntity `json:"parent,omitempty"`
+
+	Author     *NamedAndIDdEntity `json:"author,omitempty"`
+	CreatedBy  *NamedAndIDdEntity `json:"created_by,omitempty"`
+	CreatedAt  *time.Time         `json:"created_at,omitempty"`
+	ModifiedAt *time.Time         `json:"modified_at,omitempty"`
+}
+
+type StatusUpdateRequest struct {
+	// Parent is the gid of the project,
+	// portfolio or goal to post the update to.
+	Parent string `json:"parent"`
+
+	StatusType StatusType `json:"status_type"`
+
+	Title    string `json:"title,omitempty"`
+	Text     string `json:"text,omitempty"`
+	HTMLText string `json:"html_text,omitempty"`
+}
+
+var (
+	errNilStatusUpdateRequest = errors.New("expecting a non-nil statusUpdateRequest")
+	errEmptyStatusParent      = errors.New("expecting a non-empty parent")
+	errEmptyStatusText        = errors.New("expecting a non-empty text or htmlText")
+	errEmptyStatusUpdateID    = errors.New("expecting a non-empty statusUpdateID")
+)
+
+type errUnknownStatusType StatusType
+
+func (e errUnknownStatusType) Error() string {
+	return fmt.Sprintf("unknown status type %q", string(e))
+}
+
+func (sreq *StatusUpdateRequest) Validate() error {
+	if sreq == nil {
+		return errNilStatusUpdateRequest
+	}
+	if strings.TrimSpace(sreq.Parent) == "" {
+		return errEmptyStatusParent
+	}
+	if !sreq.StatusType.IsValid() {
+		return errUnknownStatusType(sreq.StatusType)
+	}
+	if strings.TrimSpace(sreq.Text) == "" && strings.TrimSpace(sreq.HTMLText) == "" {
+		return errEmptyStatusText
+	}
+	return validateRichText(sreq.HTMLText)
+}
+
+type statusUpdateWrap struct {
+	StatusUpdate *StatusUpdate `json:"data"`
+}
+
+func parseOutStatusUpdateFromData(blob []byte) (*StatusUpdate, error) {
+	sw := new(statusUpdateWrap)
+	if err := json.Unmarshal(blob, sw); err != nil {
+		return nil, err
+	}
+	return sw.StatusUpdate, nil
+}
+
+func (c *Client) CreateStatusUpdate(sreq *StatusUpdateRequest) (*StatusUpdate, error) {
+	if err := sreq.Validate(); err != nil {
+		return nil, err
+	}
+	qs, err := otils.ToURLValues(sreq)
+	if err != nil {
+		return nil, err
+	}
+	slurp, err := c.doFormReq("POST", "/status_updates", qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutStatusUpdateFromData(slurp)
+}
+
+func (c *Client) FindStatusUpdateByID(statusUpdateID string) (*StatusUpdate, error) {
+	statusUpdateID = strings.TrimSpace(statusUpdateID)
+	if statusUpdateID == "" {
+		return nil, errEmptyStatusUpdateID
+	}
+	fullURL := fmt.Sprintf("%s/status_updates/%s", baseURL, statusUpdateID)
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutStatusUpdateFromData(slurp)
+}
+
+func (c *Client) DeleteStatusUpdate(statusUpdateID string) error {
+	statusUpdateID = strings.TrimSpace(statusUpdateID)
+	if statusUpdateID == "" {
+		return errEmptyStatusUpdateID
+	}
+	fullURL := fmt.Sprintf("%s/status_updates/%s", baseURL, statusUpdateID)
+	req, err := http.NewRequest("DELETE", fullURL, nil)
+	if err != nil {
+		return err
+	}
+	_, _, err = c.doAuthReqThenSlurpBody(req)
+	return err
+}
+
+type StatusUpdatesPage struct {
+	StatusUpdates []*StatusUpdate `json:"data"`
+	Err           error
+}
+
+type statusUpdatesPager struct {
+	StatusUpdatesPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+// ListStatusUpdates pages through the status updates posted to
+// a project, portfolio or goal, with the most recent ones first.
+func (c *Client) ListStatusUpdates(parentID string) (pagesChan chan *StatusUpdatesPage, cancelChan chan<- bool, err error) {
+	parentID = strings.TrimSpace(parentID)
+	if parentID == "" {
+		return nil, nil, errEmptyStatusParent
+	}
+
+	pagesChan = make(chan *StatusUpdatesPage)
+	path := fmt.Sprintf("/status_updates?%s", url.Values{"parent": {parentID}}.Encode())
+	cancel := c.sendPages(pagesChan, path, func(slurp []byte) (interface{}, string, error) {
+		pager := new(statusUpdatesPager)
+		if err := json.Unmarshal(slurp, pager); err != nil {
+			return nil, "", err
+		}
+		return &pager.StatusUpdatesPage, pager.NextPage.nextPath(), nil
+	})
+	return pagesChan, cancel, nil
+}
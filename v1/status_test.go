@@ -0,0 +1,145 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestCreateStatusUpdate(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: statusUpdatesRoute})
+
+	tests := [...]struct {
+		req     *asana.StatusUpdateRequest
+		wantErr bool
+	}{
+		0: {req: nil, wantErr: true},
+		1: {req: &asana.StatusUpdateRequest{StatusType: asana.StatusOnTrack, Text: "ok"}, wantErr: true},
+		2: {req: &asana.StatusUpdateRequest{Parent: projectID1, StatusType: "green", Text: "ok"}, wantErr: true},
+		3: {req: &asana.StatusUpdateRequest{Parent: projectID1, StatusType: asana.StatusOnTrack}, wantErr: true},
+		4: {
+			req: &asana.StatusUpdateRequest{
+				Parent: projectID1, StatusType: asana.StatusAtRisk,
+				HTMLText: "<body><script>alert(1)</script></body>",
+			},
+			wantErr: true,
+		},
+		5: {
+			req: &asana.StatusUpdateRequest{
+				Parent: projectID1, StatusType: asana.StatusAtRisk,
+				Title: "Week 34", HTMLText: "<body>The vendor API is <strong>late</strong>.</body>",
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		su, err := client.CreateStatusUpdate(tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		if su.StatusType != tt.req.StatusType {
+			t.Errorf("#%d: gotStatusType=%q wantStatusType=%q", i, su.StatusType, tt.req.StatusType)
+		}
+		if su.Parent == nil || su.Parent.GID != tt.req.Parent {
+			t.Errorf("#%d: gotParent=%#v wantParentGID=%q", i, su.Parent, tt.req.Parent)
+		}
+	}
+}
+
+func TestListStatusUpdates(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: statusUpdatesRoute})
+
+	if _, _, err := client.ListStatusUpdates("  "); err == nil {
+		t.Errorf("expected an error for a blank parent")
+	}
+
+	pagesChan, _, err := client.ListStatusUpdates(projectID1)
+	if err != nil {
+		t.Fatalf("listing status updates: %v", err)
+	}
+	var gotStatuses []asana.StatusType
+	for page := range pagesChan {
+		if page.Err != nil {
+			t.Fatalf("page err: %v", page.Err)
+		}
+		for _, su := range page.StatusUpdates {
+			gotStatuses = append(gotStatuses, su.StatusType)
+		}
+	}
+	wantStatuses := []asana.StatusType{asana.StatusAtRisk, asana.StatusOnTrack}
+	if !reflect.DeepEqual(gotStatuses, wantStatuses) {
+		t.Errorf("gotStatuses=%v wantStatuses=%v", gotStatuses, wantStatuses)
+	}
+}
+
+func (b *backend) statusUpdatesRoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case "GET":
+		if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		query := req.URL.Query()
+		parent := query.Get("parent")
+		if parent != projectID1 {
+			return makeResp("unknown parent: "+parent, http.StatusNotFound, nil), nil
+		}
+		page := "1"
+		if query.Get("offset") == "page-2" {
+			page = "2"
+		}
+		return makeRespFromFile(fmt.Sprintf("./testdata/status-updates-%s-page-%s.json", parent, page))
+
+	default:
+		if badAuthResp, err := b.checkAuthorization(req, "POST"); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		if !strings.HasSuffix(req.URL.Path, "/status_updates") {
+			return unknownRouteResp, nil
+		}
+		if err := req.ParseForm(); err != nil {
+			return makeResp(err.Error(), http.StatusBadRequest, nil), nil
+		}
+		su := &asana.StatusUpdate{
+			GID:        "802",
+			Parent:     &asana.NamedAndIDdEntity{GID: req.PostForm.Get("parent")},
+			StatusType: asana.StatusType(req.PostForm.Get("status_type")),
+			Title:      req.PostForm.Get("title"),
+			HTMLText:   req.PostForm.Get("html_text"),
+		}
+		body := fmt.Sprintf(`{"data": %s}`, jsonMarshal(su))
+		return makeResp("201 Created", http.StatusCreated, nopCloser(body)), nil
+	}
+}
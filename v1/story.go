@@ -0,0 +1,293 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/orijtech/otils"
+)
+
+type StoryType string
+
+const (
+	// StoryComment is the type of stories written by users.
+	StoryComment StoryType = "comment"
+
+	// StorySystem is the type of stories that Asana
+	// creates to record changes to an object.
+	StorySystem StoryType = "system"
+)
+
+type Like struct {
+	GID  string             `json:"gid,omitempty"`
+	User *NamedAndIDdEntity `json:"user,omitempty"`
+}
+
+// Story is an entry in the activity feed of a task,
+// either a comment or a record of a change to the task.
+type Story struct {
+	ID        int64              `json:"id,omitempty"`
+	GID       string             `json:"gid,omitempty"`
+	CreatedAt *time.Time         `json:"created_at,omitempty"`
+	CreatedBy *NamedAndIDdEntity `json:"created_by,omitempty"`
+
+	Type StoryType `json:"type,omitempty"`
+
+	// Subtype further describes a story, for example
+	// "comment_added", "assigned" or "section_changed".
+	Subtype string `json:"resource_subtype,omitempty"`
+
+	Text     string `json:"text,omitempty"`
+	HTMLText string `json:"html_text,omitempty"`
+
+	IsPinned bool `json:"is_pinned,omitempty"`
+	IsEdited bool `json:"is_edited,omitempty"`
+
+	HeartedByMe bool                 `json:"hearted,omitempty"`
+	Hearts      []*NamedAndIDdEntity `json:"hearts,omitempty"`
+	HeartCount  int64                `json:"num_hearts,omitempty"`
+
+	LikedByMe bool    `json:"liked,omitempty"`
+	Likes     []*Like `json:"likes,omitempty"`
+	LikeCount int64   `json:"num_likes,omitempty"`
+
+	// Target is the object that the story is about.
+	Target *NamedAndIDdEntity `json:"target,omitempty"`
+}
+
+// commentSubtype is the resource_subtype of comments.
+const commentSubtype = "comment_added"
+
+// storyType returns the Type of the story, deriving it from the
+// Subtype for responses that leave out the deprecated "type" field.
+func (s *Story) storyType() StoryType {
+	switch {
+	case s.Type != "":
+		return s.Type
+	case s.Subtype == commentSubtype:
+		return StoryComment
+	case s.Subtype != "":
+		return StorySystem
+	default:
+		return ""
+	}
+}
+
+func (s *Story) IsComment() bool {
+	return s != nil && s.storyType() == StoryComment
+}
+
+func (s *Story) IsSystem() bool {
+	return s != nil && s.storyType() == StorySystem
+}
+
+// FilterComments returns only the stories that were written by users.
+func FilterComments(stories []*Story) []*Story {
+	return filterStories(stories, (*Story).IsComment)
+}
+
+// FilterSystemStories returns only the stories that
+// Asana created to record changes to an object.
+func FilterSystemStories(stories []*Story) []*Story {
+	return filterStories(stories, (*Story).IsSystem)
+}
+
+func filterStories(stories []*Story, keep func(*Story) bool) []*Story {
+	var filtered []*Story
+	for _, story := range stories {
+		if keep(story) {
+			filtered = append(filtered, story)
+		}
+	}
+	return filtered
+}
+
+// StoryRequest is the content of a comment. Only one
+// of Text and HTMLText should be set; HTMLText can
+// be produced from Markdown by package richtext.
+type StoryRequest struct {
+	Text     string `json:"text,omitempty"`
+	HTMLText string `json:"html_text,omitempty"`
+
+	// IsPinned if set, pins or unpins the comment.
+	IsPinned *bool `json:"-"`
+}
+
+var (
+	errNilStoryRequest = errors.New("expecting a non-nil storyRequest")
+	errEmptyStoryID    = errors.New("expecting a non-empty storyID")
+	errEmptyComment    = errors.New("expecting either text or htmlText to be set")
+	errTextAndHTMLText = errors.New("only one of text and htmlText can be set")
+	errEmptyStoryEdit  = errors.New("expecting either the text or the pinned state to change")
+)
+
+func (sreq *StoryRequest) Validate() error {
+	if sreq == nil {
+		return errNilStoryRequest
+	}
+	if sreq.Text != "" && sreq.HTMLText != "" {
+		return errTextAndHTMLText
+	}
+	if strings.TrimSpace(sreq.Text) == "" && strings.TrimSpace(sreq.HTMLText) == "" {
+		return errEmptyComment
+	}
+	return validateRichText(sreq.HTMLText)
+}
+
+func (sreq *StoryRequest) toURLValues() (url.Values, error) {
+	qs, err := otils.ToURLValues(sreq)
+	if err != nil {
+		return nil, err
+	}
+	if sreq.IsPinned != nil {
+		qs.Set("is_pinned", strconv.FormatBool(*sreq.IsPinned))
+	}
+	return qs, nil
+}
+
+type storyWrap struct {
+	Story *Story `json:"data"`
+}
+
+func parseOutStoryFromData(blob []byte) (*Story, error) {
+	sw := new(storyWrap)
+	if err := json.Unmarshal(blob, sw); err != nil {
+		return nil, err
+	}
+	return sw.Story, nil
+}
+
+// AddComment posts a comment onto a task.
+func (c *Client) AddComment(taskID string, sreq *StoryRequest) (*Story, error) {
+	taskID = strings.TrimSpace(taskID)
+	if taskID == "" {
+		return nil, errEmptyTaskID
+	}
+	if err := sreq.Validate(); err != nil {
+		return nil, err
+	}
+	qs, err := sreq.toURLValues()
+	if err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/tasks/%s/stories", taskID)
+	slurp, err := c.doFormReq("POST", path, qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutStoryFromData(slurp)
+}
+
+// UpdateStory edits the text of a comment or pins/unpins it.
+// Only comments can be edited, Asana rejects edits to system stories.
+func (c *Client) UpdateStory(storyID string, sreq *StoryRequest) (*Story, error) {
+	storyID = strings.TrimSpace(storyID)
+	if storyID == "" {
+		return nil, errEmptyStoryID
+	}
+	if sreq == nil {
+		return nil, errNilStoryRequest
+	}
+	if sreq.Text == "" && sreq.HTMLText == "" {
+		if sreq.IsPinned == nil {
+			return nil, errEmptyStoryEdit
+		}
+	} else if err := sreq.Validate(); err != nil {
+		return nil, err
+	}
+
+	qs, err := sreq.toURLValues()
+	if err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/stories/%s", storyID)
+	slurp, err := c.doFormReq("PUT", path, qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutStoryFromData(slurp)
+}
+
+func (c *Client) FindStoryByID(storyID string) (*Story, error) {
+	storyID = strings.TrimSpace(storyID)
+	if storyID == "" {
+		return nil, errEmptyStoryID
+	}
+	fullURL := fmt.Sprintf("%s/stories/%s", baseURL, storyID)
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutStoryFromData(slurp)
+}
+
+// DeleteStory deletes a comment. Only the
+// author of a comment is allowed to delete it.
+func (c *Client) DeleteStory(storyID string) error {
+	storyID = strings.TrimSpace(storyID)
+	if storyID == "" {
+		return errEmptyStoryID
+	}
+	fullURL := fmt.Sprintf("%s/stories/%s", baseURL, storyID)
+	req, err := http.NewRequest("DELETE", fullURL, nil)
+	if err != nil {
+		return err
+	}
+	_, _, err = c.doAuthReqThenSlurpBody(req)
+	return err
+}
+
+type StoriesPage struct {
+	Stories []*Story `json:"data"`
+	Err     error
+}
+
+type storiesPager struct {
+	StoriesPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+// ListStoriesForTask pages through the comments and system
+// stories of a task, from the oldest to the most recent.
+func (c *Client) ListStoriesForTask(taskID string) (pagesChan chan *StoriesPage, cancelChan chan<- bool, err error) {
+	taskID = strings.TrimSpace(taskID)
+	if taskID == "" {
+		return nil, nil, errEmptyTaskID
+	}
+
+	pagesChan = make(chan *StoriesPage)
+	path := fmt.Sprintf("/tasks/%s/stories", taskID)
+	cancel := c.sendPages(pagesChan, path, func(slurp []byte) (interface{}, string, error) {
+		pager := new(storiesPager)
+		if err := json.Unmarshal(slurp, pager); err != nil {
+			return nil, "", err
+		}
+		return &pager.StoriesPage, pager.NextPage.nextPath(), nil
+	})
+	return pagesChan, cancel, nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestListStoriesForTask(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: storiesRoute})
+
+	pagesChan, _, err := client.ListStoriesForTask(taskID1)
+	if err != nil {
+		t.Fatalf("listing stories: %v", err)
+	}
+
+	var stories []*asana.Story
+	for page := range pagesChan {
+		if err := page.Err; err != nil {
+			t.Fatalf("page err: %v", err)
+		}
+		stories = append(stories, page.Stories...)
+	}
+
+	if got, want := len(stories), 3; got != want {
+		t.Fatalf("got %d stories want %d", got, want)
+	}
+
+	comments := asana.FilterComments(stories)
+	if len(comments) != 1 || comments[0].GID != "402" {
+		t.Fatalf("unexpected comments: %#v", comments)
+	}
+	comment := comments[0]
+	if !comment.IsPinned || comment.LikeCount != 1 || comment.Likes[0].User.Name != "Jane Doe" {
+		t.Errorf("unexpected comment: %#v", comment)
+	}
+
+	systemStories := asana.FilterSystemStories(stories)
+	if got, want := len(systemStories), 2; got != want {
+		t.Errorf("got %d system stories want %d", got, want)
+	}
+}
+
+func TestStoryKinds(t *testing.T) {
+	tests := [...]struct {
+		story       *asana.Story
+		wantComment bool
+		wantSystem  bool
+	}{
+		0: {story: nil},
+		1: {story: &asana.Story{}},
+		2: {story: &asana.Story{Type: asana.StoryComment}, wantComment: true},
+		3: {story: &asana.Story{Type: asana.StorySystem, Subtype: "assigned"}, wantSystem: true},
+
+		// Newer responses leave out the deprecated type.
+		4: {story: &asana.Story{Subtype: "comment_added"}, wantComment: true},
+		5: {story: &asana.Story{Subtype: "section_changed"}, wantSystem: true},
+	}
+
+	for i, tt := range tests {
+		if got := tt.story.IsComment(); got != tt.wantComment {
+			t.Errorf("#%d: gotComment=%v wantComment=%v", i, got, tt.wantComment)
+		}
+		if got := tt.story.IsSystem(); got != tt.wantSystem {
+			t.Errorf("#%d: gotSystem=%v wantSystem=%v", i, got, tt.wantSystem)
+		}
+	}
+}
+
+func TestAddComment(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: storiesRoute})
+
+	tests := [...]struct {
+		taskID  string
+		req     *asana.StoryRequest
+		wantErr bool
+	}{
+		0: {taskID: taskID1, req: &asana.StoryRequest{HTMLText: "<body>Deployed <strong>v1.2</strong> to staging</body>"}},
+		1: {taskID: "", req: &asana.StoryRequest{Text: "hi"}, wantErr: true},
+		2: {taskID: taskID1, req: nil, wantErr: true},
+		3: {taskID: taskID1, req: &asana.StoryRequest{Text: "  "}, wantErr: true},
+		4: {taskID: taskID1, req: &asana.StoryRequest{Text: "a", HTMLText: "<body>a</body>"}, wantErr: true},
+		5: {taskID: taskID1, req: &asana.StoryRequest{HTMLText: "<body><div>a</div></body>"}, wantErr: true},
+	}
+
+	for i, tt := range tests {
+		story, err := client.AddComment(tt.taskID, tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		if !story.IsComment() {
+			t.Errorf("#%d: expected a comment, got %#v", i, story)
+		}
+	}
+}
+
+func (b *backend) storiesRoundTrip(req *http.Request) (*http.Response, error) {
+	if !strings.HasSuffix(req.URL.Path, "/tasks/"+taskID1+"/stories") {
+		return unknownRouteResp, nil
+	}
+
+	switch req.Method {
+	case "GET":
+		if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		return makeRespFromFile("./testdata/stories-task-id-1.json")
+
+	default:
+		if badAuthResp, err := b.checkAuthorization(req, "POST"); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		if err := req.ParseForm(); err != nil {
+			return makeResp(err.Error(), http.StatusBadRequest, nil), nil
+		}
+		htmlText := req.PostForm.Get("html_text")
+		if htmlText == "" {
+			return makeResp("expecting html_text", http.StatusBadRequest, nil), nil
+		}
+		body := `{"data": {"gid": "404", "type": "comment", "resource_subtype": "comment_added", "html_text": ` + jsonQuote(htmlText) + `}}`
+		return makeResp("200 OK", http.StatusOK, nopCloser(body)), nil
+	}
+}
+
+func jsonQuote(s string) string {
+	return string(jsonMarshal(s))
+}
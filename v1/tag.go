@@ -0,0 +1,259 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/orijtech/otils"
+)
+
+type Tag struct {
+	ID        int64      `json:"id,omitempty"`
+	GID       string     `json:"gid,omitempty"`
+	Name      string     `json:"name,omitempty"`
+	Color     string     `json:"color,omitempty"`
+	Notes     string     `json:"notes,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+
+	Workspace *NamedAndIDdEntity   `json:"workspace,omitempty"`
+	Followers []*NamedAndIDdEntity `json:"followers,omitempty"`
+
+	PermalinkURL string `json:"permalink_url,omitempty"`
+}
+
+type TagRequest struct {
+	// TagID is the gid of the tag to update.
+	TagID string `json:"-"`
+
+	// Workspace is the gid of the workspace to create the tag
+	// in. It cannot be changed once the tag has been created.
+	Workspace string `json:"workspace,omitempty"`
+
+	Name  string `json:"name,omitempty"`
+	Color string `json:"color,omitempty"`
+	Notes string `json:"notes,omitempty"`
+}
+
+var (
+	errNilTagRequest = errors.New("expecting a non-nil tagRequest")
+	errEmptyTagID    = errors.New("expecting a non-empty tagID")
+	errEmptyTagName  = errors.New("expecting a non-empty tag name")
+)
+
+func (treq *TagRequest) Validate() error {
+	if treq == nil {
+		return errNilTagRequest
+	}
+	if strings.TrimSpace(treq.Workspace) == "" {
+		return errEmptyWorkspace
+	}
+	if strings.TrimSpace(treq.Name) == "" {
+		return errEmptyTagName
+	}
+	return nil
+}
+
+type tagWrap struct {
+	Tag *Tag `json:"data"`
+}
+
+func parseOutTagFromData(blob []byte) (*Tag, error) {
+	tw := new(tagWrap)
+	if err := json.Unmarshal(blob, tw); err != nil {
+		return nil, err
+	}
+	return tw.Tag, nil
+}
+
+func (c *Client) CreateTag(treq *TagRequest) (*Tag, error) {
+	if err := treq.Validate(); err != nil {
+		return nil, err
+	}
+	qs, err := otils.ToURLValues(treq)
+	if err != nil {
+		return nil, err
+	}
+	slurp, err := c.doFormReq("POST", "/tags", qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutTagFromData(slurp)
+}
+
+// UpdateTag changes the name, color or notes of a tag.
+// The Workspace of a tag cannot be changed once it has
+// been created and trying to will return an error.
+func (c *Client) UpdateTag(treq *TagRequest) (*Tag, error) {
+	if treq == nil {
+		return nil, errNilTagRequest
+	}
+	tagID := strings.TrimSpace(treq.TagID)
+	if tagID == "" {
+		return nil, errEmptyTagID
+	}
+	if treq.Workspace != "" {
+		return nil, errImmutableWorkspace
+	}
+	qs, err := otils.ToURLValues(treq)
+	if err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/tags/%s", tagID)
+	slurp, err := c.doFormReq("PUT", path, qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutTagFromData(slurp)
+}
+
+func (c *Client) FindTagByID(tagID string) (*Tag, error) {
+	tagID = strings.TrimSpace(tagID)
+	if tagID == "" {
+		return nil, errEmptyTagID
+	}
+	fullURL := fmt.Sprintf("%s/tags/%s", baseURL, tagID)
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutTagFromData(slurp)
+}
+
+func (c *Client) DeleteTag(tagID string) error {
+	tagID = strings.TrimSpace(tagID)
+	if tagID == "" {
+		return errEmptyTagID
+	}
+	fullURL := fmt.Sprintf("%s/tags/%s", baseURL, tagID)
+	req, err := http.NewRequest("DELETE", fullURL, nil)
+	if err != nil {
+		return err
+	}
+	_, _, err = c.doAuthReqThenSlurpBody(req)
+	return err
+}
+
+// FindOrCreateTag returns the tag in the workspace whose name matches
+// name, ignoring case, and creates it if there is no such tag yet.
+// Two concurrent calls for the same new name can still both create it.
+func (c *Client) FindOrCreateTag(workspaceID, name string) (*Tag, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errEmptyTagName
+	}
+	pagesChan, cancelChan, err := c.ListTagsInWorkspace(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *Tag
+	var pageErr error
+	for page := range pagesChan {
+		if pageErr = page.Err; pageErr == nil {
+			for _, tag := range page.Tags {
+				if strings.EqualFold(strings.TrimSpace(tag.Name), name) {
+					found = tag
+					break
+				}
+			}
+		}
+		if found != nil || pageErr != nil {
+			cancelChan <- true
+			break
+		}
+	}
+
+	if pageErr != nil {
+		return nil, pageErr
+	}
+	if found != nil {
+		return found, nil
+	}
+	return c.CreateTag(&TagRequest{Workspace: workspaceID, Name: name})
+}
+
+func (c *Client) AddTagToTask(taskID, tagID string) error {
+	return c.changeTaskTag("addTag", taskID, tagID)
+}
+
+func (c *Client) RemoveTagFromTask(taskID, tagID string) error {
+	return c.changeTaskTag("removeTag", taskID, tagID)
+}
+
+func (c *Client) changeTaskTag(action, taskID, tagID string) error {
+	taskID = strings.TrimSpace(taskID)
+	if taskID == "" {
+		return errEmptyTaskID
+	}
+	tagID = strings.TrimSpace(tagID)
+	if tagID == "" {
+		return errEmptyTagID
+	}
+	qs := make(url.Values)
+	qs.Set("tag", tagID)
+	path := fmt.Sprintf("/tasks/%s/%s", taskID, action)
+	_, err := c.doFormReq("POST", path, qs)
+	return err
+}
+
+func (c *Client) TasksWithTag(tagID string) (resultsChan chan *TaskResultPage, cancelChan chan<- bool, err error) {
+	tagID = strings.TrimSpace(tagID)
+	if tagID == "" {
+		return nil, nil, errEmptyTagID
+	}
+
+	startPath := fmt.Sprintf("/tags/%s/tasks?limit=%d", tagID, defaultTaskLimit)
+	return c.doTasksPaging(startPath)
+}
+
+type TagsPage struct {
+	Tags []*Tag `json:"data"`
+	Err  error
+}
+
+type tagsPager struct {
+	TagsPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+func (c *Client) ListTagsInWorkspace(workspaceID string) (pagesChan chan *TagsPage, cancelChan chan<- bool, err error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" {
+		return nil, nil, errEmptyWorkspace
+	}
+
+	pagesChan = make(chan *TagsPage)
+	path := fmt.Sprintf("/workspaces/%s/tags", workspaceID)
+	cancel := c.sendPages(pagesChan, path, func(slurp []byte) (interface{}, string, error) {
+		pager := new(tagsPager)
+		if err := json.Unmarshal(slurp, pager); err != nil {
+			return nil, "", err
+		}
+		return &pager.TagsPage, pager.NextPage.nextPath(), nil
+	})
+	return pagesChan, cancel, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestFindOrCreateTag(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: tagsRoute})
+
+	tests := [...]struct {
+		workspaceID string
+		name        string
+		wantGID     string
+		wantErr     bool
+	}{
+		0: {workspaceID: workspaceID1, name: "bug", wantGID: "501"},
+		// Matching is case insensitive and spans pages.
+		1: {workspaceID: workspaceID1, name: "needs-triage", wantGID: "502"},
+		2: {workspaceID: workspaceID1, name: "  performance ", wantGID: "created-performance"},
+		3: {workspaceID: workspaceID1, name: "  ", wantErr: true},
+		4: {workspaceID: "", name: "bug", wantErr: true},
+	}
+
+	for i, tt := range tests {
+		tag, err := client.FindOrCreateTag(tt.workspaceID, tt.name)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		if tag.GID != tt.wantGID {
+			t.Errorf("#%d: gotGID=%q wantGID=%q", i, tag.GID, tt.wantGID)
+		}
+	}
+}
+
+func (b *backend) tagsRoundTrip(req *http.Request) (*http.Response, error) {
+	switch {
+	case req.Method == "GET" && strings.HasSuffix(req.URL.Path, "/workspaces/"+workspaceID1+"/tags"):
+		if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		page := "1"
+		switch req.URL.Query().Get("offset") {
+		case "page-2":
+			page = "2"
+		case "page-3":
+			page = "3"
+		}
+		return makeRespFromFile(fmt.Sprintf("./testdata/tags-%s-page-%s.json", workspaceID1, page))
+
+	case strings.HasSuffix(req.URL.Path, "/tags"):
+		if badAuthResp, err := b.checkAuthorization(req, "POST"); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		if err := req.ParseForm(); err != nil {
+			return makeResp(err.Error(), http.StatusBadRequest, nil), nil
+		}
+		if got := req.PostForm.Get("workspace"); got != workspaceID1 {
+			return makeResp("unexpected workspace: "+got, http.StatusBadRequest, nil), nil
+		}
+		name := req.PostForm.Get("name")
+		body := fmt.Sprintf(`{"data": {"gid": "created-%s", "name": %q}}`, name, name)
+		return makeResp("201 Created", http.StatusCreated, nopCloser(body)), nil
+
+	default:
+		return unknownRouteResp, nil
+	}
+}
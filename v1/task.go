@@ -20,28 +20,38 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"strconv"
+	"net/url"
+	"reflect"
 	"strings"
-	"sync"
 	"time"
 
+	"github.com/orijtech/asana/v1/richtext"
 	"github.com/orijtech/otils"
 )
 
 type Task struct {
 	ID          int64              `json:"id,omitempty"`
+	GID         string             `json:"gid,omitempty"`
 	Assignee    *NamedAndIDdEntity `json:"assignee,omitempty"`
 	CreatedAt   *time.Time         `json:"created_at,omitempty"`
 	Completed   bool               `json:"completed,omitempty"`
 	CompletedAt *time.Time         `json:"completed_at,omitempty"`
 
+	// Deprecated: use AssigneeSection.
 	AssigneeStatus AssigneeStatus `json:"assignee_status,omitempty"`
 
-	CustomFields []CustomField `json:"custom_fields,omitempty"`
+	// AssigneeSection is the section of the assignee's
+	// My Tasks list, see UserTaskList, that the task is in.
+	AssigneeSection *NamedAndIDdEntity `json:"assignee_section,omitempty"`
 
-	DueOn *YYYYMMDD  `json:"due_on,omitempty"`
+	CustomFields []*CustomField `json:"custom_fields,omitempty"`
+
+	DueOn *Date      `json:"due_on,omitempty"`
 	DueAt *time.Time `json:"due_at,omitempty"`
 
+	StartOn *Date      `json:"start_on,omitempty"`
+	StartAt *time.Time `json:"start_at,omitempty"`
+
 	Metadata Metadata `json:"external,omitempty"`
 
 	Followers []*NamedAndIDdEntity `json:"followers,omitempty"`
@@ -55,6 +65,10 @@ type Task struct {
 
 	Notes string `json:"notes,omitempty"`
 
+	// HTMLNotes is the rich text version of Notes.
+	// It can be produced from Markdown by package richtext.
+	HTMLNotes string `json:"html_notes,omitempty"`
+
 	Projects   []*Project `json:"projects,omitempty"`
 	ParentTask *Task      `json:"parent,omitempty"`
 
@@ -68,6 +82,7 @@ type Task struct {
 type NamedAndIDdEntity struct {
 	Name string `json:"name"`
 	ID   int64  `json:"id"`
+	GID  string `json:"gid,omitempty"`
 }
 
 type Membership struct {
@@ -75,6 +90,11 @@ type Membership struct {
 	Section *NamedAndIDdEntity `json:"section,omitempty"`
 }
 
+// AssigneeStatus is the legacy scheduling status of a task
+// in the My Tasks list of its assignee.
+//
+// Deprecated: Asana replaced the statuses with user defined
+// My Tasks sections. Use Task.AssigneeSection instead.
 type AssigneeStatus string
 
 const (
@@ -100,69 +120,8 @@ func (as AssigneeStatus) MarshalJSON() ([]byte, error) {
 	return json.Marshal(string(as))
 }
 
-type CustomField map[string]interface{}
-
 type Metadata map[string]interface{}
 
-type YYYYMMDD struct {
-	sync.RWMutex
-
-	YYYY int64
-	MM   int64
-	DD   int64
-
-	str string
-}
-
-var _ json.Marshaler = (*YYYYMMDD)(nil)
-var _ json.Unmarshaler = (*YYYYMMDD)(nil)
-
-func (ymd *YYYYMMDD) UnmarshalJSON(b []byte) error {
-	// Format of data is: 2012-03-26
-	unquoted, err := strconv.Unquote(string(b))
-	if err != nil {
-		return err
-	}
-	splits := strings.Split(unquoted, "-")
-	if len(splits) < 3 {
-		return errors.New("expecting YYYY-MM-DD")
-	}
-
-	var intified []int64
-	for _, split := range splits {
-		it, err := intifyIt(split)
-		if err != nil {
-			return err
-		}
-		intified = append(intified, it)
-	}
-
-	ymd.YYYY = intified[0]
-	ymd.MM = intified[1]
-	ymd.DD = intified[2]
-	return nil
-}
-
-func (ymd *YYYYMMDD) MarshalJSON() ([]byte, error) {
-	return json.Marshal(string(ymd.String()))
-}
-
-func (ymd *YYYYMMDD) String() string {
-	if ymd == nil {
-		return ""
-	}
-	ymd.Lock()
-	defer ymd.Unlock()
-	if ymd.str == "" {
-		ymd.str = fmt.Sprintf("%d-%d-%d", ymd.YYYY, ymd.MM, ymd.DD)
-	}
-	return ymd.str
-}
-
-func intifyIt(st string) (int64, error) {
-	return strconv.ParseInt(st, 10, 64)
-}
-
 func (c *Client) doAuthReqThenSlurpBody(req *http.Request) ([]byte, http.Header, error) {
 	req.Header.Set("Authorization", c.personalAccessTokenAuthValue())
 	res, err := c.httpClient().Do(req)
@@ -188,6 +147,29 @@ func (c *Client) doAuthReqThenSlurpBody(req *http.Request) ([]byte, http.Header,
 	return slurp, res.Header, err
 }
 
+// doFormReq sends the url-encoded form in qs to path using method.
+func (c *Client) doFormReq(method, path string, qs url.Values) ([]byte, error) {
+	fullURL := fmt.Sprintf("%s%s", baseURL, path)
+	req, err := http.NewRequest(method, fullURL, strings.NewReader(qs.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	return slurp, err
+}
+
+// validateRichText checks that a non-empty rich
+// text field only uses the tags that Asana supports.
+func validateRichText(html string) error {
+	if html == "" {
+		return nil
+	}
+	return richtext.Validate(html)
+}
+
+var errNilTaskRequest = errors.New("expecting a non-nil taskRequest")
+
 var readOnlyFields = []string{
 	"num_hearts",
 }
@@ -197,8 +179,41 @@ type taskResultWrap struct {
 }
 
 func (c *Client) CreateTask(t *TaskRequest) (*Task, error) {
+	if t == nil {
+		return nil, errNilTaskRequest
+	}
+	if err := t.ValidateDates(); err != nil {
+		return nil, err
+	}
+	if err := validateRichText(t.HTMLNotes); err != nil {
+		return nil, err
+	}
+
 	// This endpoint takes in url-encoded data
-	qs, err := otils.ToURLValues(t)
+	qs, err := t.toURLValues()
+	if err != nil {
+		return nil, err
+	}
+	slurp, err := c.doFormReq("POST", "/tasks", qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutTaskFromData(slurp)
+}
+
+var (
+	errCompleteAndIncomplete = errors.New("a task cannot be marked both complete and incomplete")
+	errClearAndSetAssignee   = errors.New("the assignee cannot be both set and cleared")
+)
+
+func (treq *TaskRequest) toURLValues() (url.Values, error) {
+	if treq.Completed && treq.MarkIncomplete {
+		return nil, errCompleteAndIncomplete
+	}
+	if treq.ClearAssignee && treq.Assignee != "" {
+		return nil, errClearAndSetAssignee
+	}
+	qs, err := otils.ToURLValues(treq)
 	if err != nil {
 		return nil, err
 	}
@@ -206,15 +221,46 @@ func (c *Client) CreateTask(t *TaskRequest) (*Task, error) {
 	for _, field := range readOnlyFields {
 		qs.Del(field)
 	}
+	if treq.MarkIncomplete {
+		qs.Set("completed", "false")
+	}
+	if treq.ClearAssignee {
+		qs.Set("assignee", "")
+	}
+	if treq.ClearDueDate {
+		qs.Set("due_on", "")
+	}
+	if treq.ClearStartDate {
+		qs.Set("start_on", "")
+	}
+	treq.CustomFields.addTo(qs)
+	return qs, nil
+}
+
+// UpdateTask changes the attributes of the task with taskID.
+// Only the fields that are set in the request are modified, use
+// MarkIncomplete, ClearAssignee, ClearDueDate and ClearStartDate
+// to unset them.
+func (c *Client) UpdateTask(taskID string, treq *TaskRequest) (*Task, error) {
+	taskID = strings.TrimSpace(taskID)
+	if taskID == "" {
+		return nil, errEmptyTaskID
+	}
+	if treq == nil {
+		return nil, errNilTaskRequest
+	}
+	if err := treq.ValidateDates(); err != nil {
+		return nil, err
+	}
+	if err := validateRichText(treq.HTMLNotes); err != nil {
+		return nil, err
+	}
 
-	fullURL := fmt.Sprintf("%s/tasks", baseURL)
-	queryStr := qs.Encode()
-	req, err := http.NewRequest("POST", fullURL, strings.NewReader(queryStr))
+	qs, err := treq.toURLValues()
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	slurp, err := c.doFormReq("PUT", fmt.Sprintf("/tasks/%s", taskID), qs)
 	if err != nil {
 		return nil, err
 	}
@@ -244,7 +290,7 @@ type TaskRequest struct {
 	Page        int        `json:"page,omitempty"`
 	Limit       int        `json:"limit,omitempty"`
 	MaxRetries  int        `json:"max_retries,omitempty"`
-	Assignee    string     `json:"assignee"`
+	Assignee    string     `json:"assignee,omitempty"`
 	ProjectID   string     `json:"project,omitempty"`
 	Workspace   string     `json:"workspace,omitempty"`
 	ID          int64      `json:"id,omitempty"`
@@ -252,13 +298,36 @@ type TaskRequest struct {
 	Completed   bool       `json:"completed,omitempty"`
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
 
+	// MarkIncomplete marks a completed task as incomplete
+	// again, which leaving Completed unset does not do.
+	MarkIncomplete bool `json:"-"`
+
+	// ClearAssignee unassigns the task when updating it.
+	ClearAssignee bool `json:"-"`
+
+	// Deprecated: use AssigneeSection.
 	AssigneeStatus AssigneeStatus `json:"assignee_status,omitempty"`
 
-	CustomFields []CustomField `json:"custom_fields,omitempty"`
+	// AssigneeSection is the gid of the section of the assignee's
+	// My Tasks list to move the task to. The sections can be listed
+	// with ListSectionsForUserTaskList.
+	AssigneeSection string `json:"assignee_section,omitempty"`
+
+	// CustomFields maps the gids of custom fields to the values to set.
+	CustomFields CustomFieldValues `json:"-"`
 
-	DueOn *YYYYMMDD  `json:"due_on,omitempty"`
+	DueOn *Date      `json:"due_on,omitempty"`
 	DueAt *time.Time `json:"due_at,omitempty"`
 
+	StartOn *Date      `json:"start_on,omitempty"`
+	StartAt *time.Time `json:"start_at,omitempty"`
+
+	// ClearDueDate and ClearStartDate remove the due and the start
+	// date or time of the task when updating it. They are sent as
+	// empty values, which Asana treats as null.
+	ClearDueDate   bool `json:"-"`
+	ClearStartDate bool `json:"-"`
+
 	Metadata Metadata `json:"external,omitempty"`
 
 	Followers []UserID `json:"followers,omitempty"`
@@ -272,6 +341,10 @@ type TaskRequest struct {
 
 	Notes string `json:"notes,omitempty"`
 
+	// HTMLNotes is the rich text version of Notes.
+	// It can be produced from Markdown by package richtext.
+	HTMLNotes string `json:"html_notes,omitempty"`
+
 	Projects   []*NamedAndIDdEntity `json:"projects,omitempty"`
 	ParentTask *Task                `json:"parent,omitempty"`
 
@@ -280,6 +353,111 @@ type TaskRequest struct {
 	Tags []*NamedAndIDdEntity `json:"tags,omitempty"`
 }
 
+var (
+	errDueOnAndDueAt       = errors.New("due_on and due_at are mutually exclusive, set only one of them")
+	errStartOnAndStartAt   = errors.New("start_on and start_at are mutually exclusive, set only one of them")
+	errStartWithoutDue     = errors.New("a start date requires a due date to also be set")
+	errStartAtWithoutDueAt = errors.New("start_at requires due_at to also be set")
+	errStartAfterDue       = errors.New("the start date cannot be after the due date")
+	errClearAndSetDue      = errors.New("the due date cannot be both set and cleared")
+	errClearAndSetStart    = errors.New("the start date cannot be both set and cleared")
+)
+
+// ValidateDates checks that the date fields of the request
+// are a combination that Asana accepts: only one of due_on and
+// due_at, only one of start_on and start_at, a start date only
+// alongside a due date, and the start not coming after the due date.
+func (treq *TaskRequest) ValidateDates() error {
+	if treq == nil {
+		return nil
+	}
+	if treq.DueOn != nil && treq.DueAt != nil {
+		return errDueOnAndDueAt
+	}
+	if treq.StartOn != nil && treq.StartAt != nil {
+		return errStartOnAndStartAt
+	}
+
+	hasDue := treq.DueOn != nil || treq.DueAt != nil
+	hasStart := treq.StartOn != nil || treq.StartAt != nil
+	if treq.ClearDueDate && hasDue {
+		return errClearAndSetDue
+	}
+	if treq.ClearStartDate && hasStart {
+		return errClearAndSetStart
+	}
+	if hasStart && !hasDue {
+		return errStartWithoutDue
+	}
+	if treq.StartAt != nil && treq.DueAt == nil {
+		return errStartAtWithoutDueAt
+	}
+
+	if !hasStart {
+		return nil
+	}
+	start := startTime(treq.StartOn, treq.StartAt, time.UTC)
+	deadline := deadlineTime(treq.DueOn, treq.DueAt, time.UTC)
+	// A start date begins at midnight hence it must
+	// be strictly before the deadline, unlike start_at
+	// which is allowed to coincide with due_at.
+	if start.After(deadline) || (treq.StartOn != nil && start.Equal(deadline)) {
+		return errStartAfterDue
+	}
+	return nil
+}
+
+// Deadline returns the instant in loc from which the task is overdue.
+// For a task with only a due date, that is midnight at the end of the
+// due date in loc. The returned bool is false if the task has no due date.
+func (t *Task) Deadline(loc *time.Location) (time.Time, bool) {
+	if t == nil || (t.DueOn == nil && t.DueAt == nil) {
+		return time.Time{}, false
+	}
+	return deadlineTime(t.DueOn, t.DueAt, loc), true
+}
+
+// Start returns the instant in loc at which work on the task is
+// scheduled to start. For a task with only a start date, that is
+// midnight at the beginning of the start date in loc.
+// The returned bool is false if the task has no start date.
+func (t *Task) Start(loc *time.Location) (time.Time, bool) {
+	if t == nil || (t.StartOn == nil && t.StartAt == nil) {
+		return time.Time{}, false
+	}
+	return startTime(t.StartOn, t.StartAt, loc), true
+}
+
+// IsOverdue reports whether the incomplete task's deadline,
+// as computed in loc, is before now.
+func (t *Task) IsOverdue(now time.Time, loc *time.Location) bool {
+	if t == nil || t.Completed {
+		return false
+	}
+	deadline, ok := t.Deadline(loc)
+	return ok && now.After(deadline)
+}
+
+func deadlineTime(dueOn *Date, dueAt *time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	if dueAt != nil {
+		return dueAt.In(loc)
+	}
+	return dueOn.AddDays(1).In(loc)
+}
+
+func startTime(startOn *Date, startAt *time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	if startAt != nil {
+		return startAt.In(loc)
+	}
+	return startOn.In(loc)
+}
+
 type listTaskWrap struct {
 	Tasks []*Task `json:"data"`
 }
@@ -301,6 +479,11 @@ func (treq *TaskRequest) fillWithDefaults() {
 	}
 }
 
+// ListMyTasks lists the tasks assigned to the current user.
+//
+// Deprecated: it requires the Workspace to be set and ignores the
+// My Tasks sections. Use FindUserTaskListForUser and then
+// TasksForUserTaskList instead.
 func (c *Client) ListMyTasks(treq *TaskRequest) (chan *TaskResultPage, error) {
 	theReq := new(TaskRequest)
 	if treq != nil {
@@ -318,6 +501,88 @@ func (c *Client) ListMyTasks(treq *TaskRequest) (chan *TaskResultPage, error) {
 	return pageChan, err
 }
 
+// TaskQuery describes the filters for listing tasks through
+// GET /tasks. Asana requires either a ProjectID, a SectionID or
+// both an Assignee and a Workspace to be set.
+type TaskQuery struct {
+	Assignee  string `json:"assignee,omitempty"`
+	Workspace string `json:"workspace,omitempty"`
+	ProjectID string `json:"project,omitempty"`
+	SectionID string `json:"section,omitempty"`
+
+	// CompletedSince only returns tasks that are either
+	// incomplete or that have been completed since this time.
+	CompletedSince *time.Time `json:"completed_since,omitempty"`
+
+	// IncompleteOnly if set, only returns tasks that are not
+	// yet completed. It takes precedence over CompletedSince.
+	IncompleteOnly bool `json:"-"`
+
+	// ModifiedSince only returns tasks that have been
+	// modified since this time.
+	ModifiedSince *time.Time `json:"modified_since,omitempty"`
+
+	Limit int `json:"limit,omitempty"`
+
+	// OptFields if set, lists the fields to be
+	// included in each returned task.
+	OptFields []string `json:"-"`
+}
+
+var (
+	errNilTaskQuery      = errors.New("expecting a non-nil taskQuery")
+	errTaskQueryNoFilter = errors.New("expecting either a project, a section or both an assignee and workspace")
+)
+
+func (tq *TaskQuery) Validate() error {
+	if tq == nil {
+		return errNilTaskQuery
+	}
+	if strings.TrimSpace(tq.ProjectID) != "" || strings.TrimSpace(tq.SectionID) != "" {
+		return nil
+	}
+	if strings.TrimSpace(tq.Assignee) != "" && strings.TrimSpace(tq.Workspace) != "" {
+		return nil
+	}
+	return errTaskQueryNoFilter
+}
+
+const completedSinceNow = "now"
+
+func (tq *TaskQuery) toURLValues() (url.Values, error) {
+	copyQuery := *tq
+	if copyQuery.Limit <= 0 {
+		copyQuery.Limit = defaultTaskLimit
+	}
+	qs, err := otils.ToURLValues(&copyQuery)
+	if err != nil {
+		return nil, err
+	}
+	if tq.IncompleteOnly {
+		qs.Set("completed_since", completedSinceNow)
+	}
+	if len(tq.OptFields) > 0 {
+		qs.Set("opt_fields", strings.Join(tq.OptFields, ","))
+	}
+	return qs, nil
+}
+
+// QueryForTasks pages through the tasks that match the filters in the TaskQuery.
+// Combined with ModifiedSince, it can be used to only retrieve the tasks
+// that have changed since a previous sync.
+func (c *Client) QueryForTasks(tq *TaskQuery) (resultsChan chan *TaskResultPage, cancelChan chan<- bool, err error) {
+	if err := tq.Validate(); err != nil {
+		return nil, nil, err
+	}
+	qs, err := tq.toURLValues()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	path := fmt.Sprintf("/tasks?%s", qs.Encode())
+	return c.doTasksPaging(path)
+}
+
 type WorkspacePage struct {
 	Err        error
 	Workspaces []*Workspace `json:"data,omitempty"`
@@ -331,40 +596,112 @@ type pageToken struct {
 	URI    string `json:"uri"`
 }
 
-type Workspace NamedAndIDdEntity
+// nextPath returns the path of the page that pt points
+// to or "" if there is no next page to retrieve.
+func (pt *pageToken) nextPath() string {
+	if pt == nil {
+		return ""
+	}
+	return pt.Path
+}
 
-func (c *Client) ListMyWorkspaces() (chan *WorkspacePage, error) {
-	wspChan := make(chan *WorkspacePage)
-	go func() {
-		defer close(wspChan)
+// pageThrough retrieves the page at path and passes its body to
+// handlePage which returns the path of the next page, or "" once
+// the last page has been handled. It stops early and without an
+// error if cancel is signalled and returns the first error from
+// either retrieving a page or from handlePage.
+func (c *Client) pageThrough(path string, cancel <-chan bool, handlePage func(slurp []byte) (nextPath string, err error)) error {
+	for path != "" {
+		select {
+		case <-cancel:
+			return nil
+		default:
+		}
 
-		path := "/workspaces"
-		for {
-			fullURL := fmt.Sprintf("%s%s", baseURL, path)
-			req, _ := http.NewRequest("GET", fullURL, nil)
-			slurp, _, err := c.doAuthReqThenSlurpBody(req)
-			if err != nil {
-				wspChan <- &WorkspacePage{Err: err}
-				return
-			}
+		fullURL := fmt.Sprintf("%s%s", baseURL, path)
+		req, err := http.NewRequest("GET", fullURL, nil)
+		if err != nil {
+			return err
+		}
+		slurp, _, err := c.doAuthReqThenSlurpBody(req)
+		if err != nil {
+			return err
+		}
+		if path, err = handlePage(slurp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-			page := new(WorkspacePage)
-			if err := json.Unmarshal(slurp, page); err != nil {
-				page.Err = err
-			}
+// sendPages pages through the results from path on in a goroutine
+// and sends them on pagesChan, which must be a channel of pointers
+// to a page struct with an "Err error" field such as chan *TeamPage.
+// decode parses the body of a page and returns the page to send and
+// the path of the next page. Errors are sent as a page with only Err
+// set. pagesChan is closed once paging stops, after the last page,
+// an error or a value on the returned channel. Pages are sent while
+// also waiting for the cancellation so cancelling stops the goroutine
+// even if the pages are no longer received.
+func (c *Client) sendPages(pagesChan interface{}, path string, decode func(slurp []byte) (page interface{}, nextPath string, err error)) (cancelChan chan<- bool) {
+	chanValue := reflect.ValueOf(pagesChan)
+	pageType := chanValue.Type().Elem().Elem()
+	if _, ok := pageType.FieldByName("Err"); !ok {
+		panic(fmt.Sprintf("asana: %v has no Err field", pageType))
+	}
+
+	cancel := make(chan bool, 1)
+	send := func(page reflect.Value) (sent bool) {
+		chosen, _, _ := reflect.Select([]reflect.SelectCase{
+			{Dir: reflect.SelectSend, Chan: chanValue, Send: page},
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(cancel)},
+		})
+		return chosen == 0
+	}
 
-			wspChan <- page
+	go func() {
+		defer chanValue.Close()
 
-			if np := page.NextPage; np != nil && np.Path == "" {
-				path = np.Path
-			} else {
-				// End of this pagination
-				break
+		err := c.pageThrough(path, cancel, func(slurp []byte) (string, error) {
+			page, nextPath, err := decode(slurp)
+			if err != nil {
+				return "", err
 			}
+			if !send(reflect.ValueOf(page)) {
+				return "", nil
+			}
+			return nextPath, nil
+		})
+		if err != nil {
+			errPage := reflect.New(pageType)
+			errPage.Elem().FieldByName("Err").Set(reflect.ValueOf(err))
+			send(errPage)
 		}
 	}()
 
-	return wspChan, nil
+	return cancel
+}
+
+// ListMyWorkspaces pages through the workspaces that the current user is a member of.
+// The pages have to be read until the channel is closed, use ListAllMyWorkspaces
+// to be able to stop early.
+func (c *Client) ListMyWorkspaces() (chan *WorkspacePage, error) {
+	pagesChan, _, err := c.ListAllMyWorkspaces()
+	return pagesChan, err
+}
+
+// ListAllMyWorkspaces is like ListMyWorkspaces but also returns
+// a channel to cancel the paging with.
+func (c *Client) ListAllMyWorkspaces() (pagesChan chan *WorkspacePage, cancelChan chan<- bool, err error) {
+	pagesChan = make(chan *WorkspacePage)
+	cancel := c.sendPages(pagesChan, "/workspaces", func(slurp []byte) (interface{}, string, error) {
+		page := new(WorkspacePage)
+		if err := json.Unmarshal(slurp, page); err != nil {
+			return nil, "", err
+		}
+		return page, page.NextPage.nextPath(), nil
+	})
+	return pagesChan, cancel, nil
 }
 
 var errEmptyTaskID = errors.New("expecting a non-empty taskID")
@@ -395,43 +732,14 @@ func (c *Client) ListTasksForProject(treq *TaskRequest) (resultsChan chan *TaskR
 
 func (c *Client) doTasksPaging(path string) (resultsChan chan *TaskResultPage, cancelChan chan<- bool, err error) {
 	tasksPageChan := make(chan *TaskResultPage)
-	cancelChan = make(chan bool, 1)
-
-	go func() {
-		defer close(tasksPageChan)
-
-		for {
-			fullURL := fmt.Sprintf("%s%s", baseURL, path)
-			req, err := http.NewRequest("GET", fullURL, nil)
-			if err != nil {
-				tasksPageChan <- &TaskResultPage{Err: err}
-				return
-			}
-
-			slurp, _, err := c.doAuthReqThenSlurpBody(req)
-			if err != nil {
-				tasksPageChan <- &TaskResultPage{Err: err}
-				return
-			}
-
-			pager := new(taskPager)
-			if err := json.Unmarshal(slurp, pager); err != nil {
-				pager.Err = err
-			}
-
-			taskPage := pager.TaskResultPage
-			tasksPageChan <- &taskPage
-
-			if np := pager.NextPage; np != nil && np.Path == "" {
-				path = np.Path
-			} else {
-				// End of this pagination
-				break
-			}
+	cancel := c.sendPages(tasksPageChan, path, func(slurp []byte) (interface{}, string, error) {
+		pager := new(taskPager)
+		if err := json.Unmarshal(slurp, pager); err != nil {
+			return nil, "", err
 		}
-	}()
-
-	return tasksPageChan, cancelChan, nil
+		return &pager.TaskResultPage, pager.NextPage.nextPath(), nil
+	})
+	return tasksPageChan, cancel, nil
 }
 
 func (c *Client) DeleteTask(taskID string) error {
@@ -0,0 +1,305 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestQueryForTasks(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: queryForTasksRoute})
+
+	modifiedSince := time.Date(2017, 3, 5, 0, 0, 0, 0, time.UTC)
+	tests := [...]struct {
+		query   *asana.TaskQuery
+		wantErr bool
+		wantIDs []int64
+	}{
+		0: {
+			query: &asana.TaskQuery{
+				Assignee:       asana.MeAsUser,
+				Workspace:      "331783765164429",
+				ModifiedSince:  &modifiedSince,
+				IncompleteOnly: true,
+				Limit:          2,
+				OptFields:      []string{"name", "completed", "modified_at"},
+			},
+			wantIDs: []int64{1001, 1002, 1003},
+		},
+		1: {
+			query:   nil,
+			wantErr: true,
+		},
+		2: {
+			// Assignee without a workspace.
+			query:   &asana.TaskQuery{Assignee: asana.MeAsUser},
+			wantErr: true,
+		},
+		3: {
+			query:   &asana.TaskQuery{},
+			wantErr: true,
+		},
+	}
+
+	for i, tt := range tests {
+		pagesChan, _, err := client.QueryForTasks(tt.query)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+
+		var gotIDs []int64
+		for page := range pagesChan {
+			if err := page.Err; err != nil {
+				t.Errorf("#%d: page err: %v", i, err)
+				continue
+			}
+			for _, task := range page.Tasks {
+				gotIDs = append(gotIDs, task.ID)
+			}
+		}
+
+		if !reflect.DeepEqual(gotIDs, tt.wantIDs) {
+			t.Errorf("#%d: gotIDs=%v wantIDs=%v", i, gotIDs, tt.wantIDs)
+		}
+	}
+}
+
+func (b *backend) queryForTasksRoundTrip(req *http.Request) (*http.Response, error) {
+	if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
+		return badAuthResp, err
+	}
+
+	query := req.URL.Query()
+	wantParams := map[string]string{
+		"assignee":        asana.MeAsUser,
+		"workspace":       "331783765164429",
+		"completed_since": "now",
+		"modified_since":  "2017-03-05T00:00:00Z",
+	}
+	for key, want := range wantParams {
+		if got := query.Get(key); got != want {
+			return makeResp("unexpected value for "+key+": "+got, http.StatusBadRequest, nil), nil
+		}
+	}
+
+	switch offset := query.Get("offset"); offset {
+	case "":
+		if got, want := query.Get("opt_fields"), "name,completed,modified_at"; got != want {
+			return makeResp("unexpected opt_fields: "+got, http.StatusBadRequest, nil), nil
+		}
+		return makeRespFromFile("./testdata/tasks-query-page-1.json")
+	case "page-2":
+		return makeRespFromFile("./testdata/tasks-query-page-2.json")
+	default:
+		return makeResp("unknown offset: "+offset, http.StatusBadRequest, nil), nil
+	}
+}
+
+func TestUpdateTaskForm(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	b := &backend{route: taskFormRoute}
+	client.SetHTTPRoundTripper(b)
+
+	tests := [...]struct {
+		req      *asana.TaskRequest
+		wantForm url.Values
+		wantErr  bool
+	}{
+		0: {
+			req:      &asana.TaskRequest{MarkIncomplete: true},
+			wantForm: url.Values{"completed": {"false"}},
+		},
+		1: {
+			req:      &asana.TaskRequest{Completed: true},
+			wantForm: url.Values{"completed": {"true"}},
+		},
+		2: {
+			req:      &asana.TaskRequest{ClearDueDate: true, ClearStartDate: true},
+			wantForm: url.Values{"due_on": {""}, "start_on": {""}},
+		},
+		3: {
+			// Fields that are not set, such as the assignee, are not sent.
+			req:      &asana.TaskRequest{Name: "Renamed"},
+			wantForm: url.Values{"name": {"Renamed"}},
+		},
+		4: {
+			req:      &asana.TaskRequest{ClearAssignee: true},
+			wantForm: url.Values{"assignee": {""}},
+		},
+		5: {
+			req:      &asana.TaskRequest{Assignee: "user-1"},
+			wantForm: url.Values{"assignee": {"user-1"}},
+		},
+		6: {req: &asana.TaskRequest{Completed: true, MarkIncomplete: true}, wantErr: true},
+		7: {req: &asana.TaskRequest{Assignee: "user-1", ClearAssignee: true}, wantErr: true},
+	}
+
+	for i, tt := range tests {
+		b.lastForm = nil
+		_, err := client.UpdateTask(taskID1, tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			if b.lastForm != nil {
+				t.Errorf("#%d: unexpectedly sent %v", i, b.lastForm)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(b.lastForm, tt.wantForm) {
+			t.Errorf("#%d:\ngotForm:  %v\nwantForm: %v", i, b.lastForm, tt.wantForm)
+		}
+	}
+}
+
+// taskFormRoundTrip records the form that
+// a task was updated with in lastForm.
+func (b *backend) taskFormRoundTrip(req *http.Request) (*http.Response, error) {
+	if badAuthResp, err := b.checkAuthorization(req, "PUT"); err != nil || badAuthResp != nil {
+		return badAuthResp, err
+	}
+	if err := req.ParseForm(); err != nil {
+		return makeResp(err.Error(), http.StatusBadRequest, nil), nil
+	}
+	b.lastForm = req.PostForm
+	task := &asana.Task{GID: taskID1}
+	return makeResp("200 OK", http.StatusOK, nopCloser(fmt.Sprintf(`{"data": %s}`, jsonMarshal(task)))), nil
+}
+
+func TestTaskRequestValidateDates(t *testing.T) {
+	d := func(year int, month time.Month, day int) *asana.Date {
+		return &asana.Date{Year: year, Month: month, Day: day}
+	}
+	at := func(year int, month time.Month, day, hour int) *time.Time {
+		t := time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	tests := [...]struct {
+		req     *asana.TaskRequest
+		wantErr bool
+	}{
+		0:  {req: &asana.TaskRequest{}},
+		1:  {req: &asana.TaskRequest{DueOn: d(2017, time.March, 5)}},
+		2:  {req: &asana.TaskRequest{DueOn: d(2017, time.March, 5), DueAt: at(2017, time.March, 5, 10)}, wantErr: true},
+		3:  {req: &asana.TaskRequest{StartOn: d(2017, time.March, 1)}, wantErr: true},
+		4:  {req: &asana.TaskRequest{StartOn: d(2017, time.March, 1), DueOn: d(2017, time.March, 5)}},
+		5:  {req: &asana.TaskRequest{StartOn: d(2017, time.March, 6), DueOn: d(2017, time.March, 5)}, wantErr: true},
+		6:  {req: &asana.TaskRequest{StartOn: d(2017, time.March, 5), DueOn: d(2017, time.March, 5)}},
+		7:  {req: &asana.TaskRequest{StartAt: at(2017, time.March, 1, 9), DueOn: d(2017, time.March, 5)}, wantErr: true},
+		8:  {req: &asana.TaskRequest{StartAt: at(2017, time.March, 1, 9), DueAt: at(2017, time.March, 5, 17)}},
+		9:  {req: &asana.TaskRequest{StartOn: d(2017, time.March, 1), StartAt: at(2017, time.March, 1, 9), DueAt: at(2017, time.March, 5, 17)}, wantErr: true},
+		10: {req: &asana.TaskRequest{ClearDueDate: true, DueOn: d(2017, time.March, 5)}, wantErr: true},
+		11: {req: &asana.TaskRequest{ClearStartDate: true, StartOn: d(2017, time.March, 1), DueOn: d(2017, time.March, 5)}, wantErr: true},
+		12: {req: &asana.TaskRequest{ClearDueDate: true, ClearStartDate: true}},
+	}
+
+	for i, tt := range tests {
+		err := tt.req.ValidateDates()
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+		}
+	}
+}
+
+func TestTaskDeadline(t *testing.T) {
+	pst := time.FixedZone("PST", -8*60*60)
+	dueAt := time.Date(2017, time.March, 6, 1, 0, 0, 0, time.UTC)
+
+	tests := [...]struct {
+		task        *asana.Task
+		loc         *time.Location
+		want        string
+		wantOK      bool
+		wantOverdue bool
+	}{
+		0: {task: &asana.Task{}, loc: pst},
+		1: {
+			task:   &asana.Task{DueOn: &asana.Date{Year: 2017, Month: time.March, Day: 5}},
+			loc:    pst,
+			want:   "2017-03-06T00:00:00-08:00",
+			wantOK: true,
+		},
+		2: {
+			task:        &asana.Task{DueOn: &asana.Date{Year: 2017, Month: time.March, Day: 5}},
+			loc:         time.UTC,
+			want:        "2017-03-06T00:00:00Z",
+			wantOK:      true,
+			wantOverdue: true,
+		},
+		3: {
+			task:        &asana.Task{DueAt: &dueAt},
+			loc:         pst,
+			want:        "2017-03-05T17:00:00-08:00",
+			wantOK:      true,
+			wantOverdue: true,
+		},
+		4: {
+			task:   &asana.Task{DueAt: &dueAt, Completed: true},
+			loc:    pst,
+			want:   "2017-03-05T17:00:00-08:00",
+			wantOK: true,
+		},
+	}
+
+	now := time.Date(2017, time.March, 6, 3, 0, 0, 0, time.UTC)
+	for i, tt := range tests {
+		got, ok := tt.task.Deadline(tt.loc)
+		if ok != tt.wantOK {
+			t.Errorf("#%d: gotOK=%v wantOK=%v", i, ok, tt.wantOK)
+			continue
+		}
+		if ok {
+			if gotStr := got.Format(time.RFC3339); gotStr != tt.want {
+				t.Errorf("#%d: got=%s want=%s", i, gotStr, tt.want)
+			}
+		}
+		if gotOverdue := tt.task.IsOverdue(now, tt.loc); gotOverdue != tt.wantOverdue {
+			t.Errorf("#%d: gotOverdue=%v wantOverdue=%v", i, gotOverdue, tt.wantOverdue)
+		}
+	}
+}
@@ -18,7 +18,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"strings"
 
@@ -29,7 +28,28 @@ type teamCRUDData struct {
 	UserID UserID `json:"user"`
 }
 
-type Team NamedAndIDdEntity
+type Team struct {
+	Name string `json:"name"`
+	ID   int64  `json:"id"`
+	GID  string `json:"gid,omitempty"`
+
+	Description     string `json:"description,omitempty"`
+	HTMLDescription string `json:"html_description,omitempty"`
+
+	Organization *NamedAndIDdEntity `json:"organization,omitempty"`
+
+	Visibility TeamVisibility `json:"visibility,omitempty"`
+
+	PermalinkURL string `json:"permalink_url,omitempty"`
+}
+
+type TeamVisibility string
+
+const (
+	TeamSecret        TeamVisibility = "secret"
+	TeamRequestToJoin TeamVisibility = "request_to_join"
+	TeamPublic        TeamVisibility = "public"
+)
 
 type TeamRequest struct {
 	// TeamID is a globally unique identifier for the team.
@@ -175,93 +195,155 @@ func (c *Client) ListAllTeamsForUser(treq *TeamRequest) (pagesChan chan *TeamPag
 
 func (c *Client) pageForTeams(path string) (pagesChan chan *TeamPage, cancelChan chan<- bool, err error) {
 	pagesChan = make(chan *TeamPage)
-	cancelChan = make(chan bool, 1)
-
-	go func() {
-		defer close(pagesChan)
-
-		for {
-			fullURL := fmt.Sprintf("%s%s", baseURL, path)
-			log.Printf("fullURL: %q\n", fullURL)
-			req, _ := http.NewRequest("GET", fullURL, nil)
-			slurp, _, err := c.doAuthReqThenSlurpBody(req)
-			if err != nil {
-				pagesChan <- &TeamPage{Err: err}
-				return
-			}
-
-			pager := new(teamPager)
-			if err := json.Unmarshal(slurp, pager); err != nil {
-				pager.Err = err
-			}
-
-			teamPage := pager.TeamPage
-			pagesChan <- &teamPage
-
-			if np := pager.NextPage; np != nil && np.Path == "" {
-				path = np.Path
-			} else {
-				// End of this pagination
-				break
-			}
+	cancel := c.sendPages(pagesChan, path, func(slurp []byte) (interface{}, string, error) {
+		pager := new(teamPager)
+		if err := json.Unmarshal(slurp, pager); err != nil {
+			return nil, "", err
 		}
-	}()
+		return &pager.TeamPage, pager.NextPage.nextPath(), nil
+	})
+	return pagesChan, cancel, nil
+}
 
-	return pagesChan, cancelChan, nil
+// ListAllUsersInTeam is the same as ListUsersInTeam.
+func (c *Client) ListAllUsersInTeam(teamID string) (pagesChan chan *UsersPage, cancelChan chan<- bool, err error) {
+	return c.ListUsersInTeam(teamID)
 }
 
-type UsersPage struct {
-	Users []*User `json:"data"`
-	Err   error
+// TeamDetailsRequest creates a team or changes its details.
+type TeamDetailsRequest struct {
+	// TeamID is the gid of the team to update.
+	TeamID string `json:"-"`
+
+	// Organization is the gid of the organization to create the
+	// team in. It cannot be changed once the team has been created.
+	Organization string `json:"organization,omitempty"`
+
+	Name            string         `json:"name,omitempty"`
+	Description     string         `json:"description,omitempty"`
+	HTMLDescription string         `json:"html_description,omitempty"`
+	Visibility      TeamVisibility `json:"visibility,omitempty"`
+}
+
+var (
+	errNilTeamDetailsRequest = errors.New("expecting a non-nil teamDetailsRequest")
+	errEmptyTeamName         = errors.New("expecting a non-empty team name")
+	errImmutableOrganization = errors.New("organization once set cannot be modified")
+)
+
+func (tdr *TeamDetailsRequest) Validate() error {
+	if tdr == nil {
+		return errNilTeamDetailsRequest
+	}
+	if strings.TrimSpace(tdr.Organization) == "" {
+		return errEmptyOrganizationID
+	}
+	if strings.TrimSpace(tdr.Name) == "" {
+		return errEmptyTeamName
+	}
+	return validateRichText(tdr.HTMLDescription)
+}
+
+func (c *Client) CreateTeam(tdr *TeamDetailsRequest) (*Team, error) {
+	if err := tdr.Validate(); err != nil {
+		return nil, err
+	}
+	qs, err := otils.ToURLValues(tdr)
+	if err != nil {
+		return nil, err
+	}
+	slurp, err := c.doFormReq("POST", "/teams", qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutTeamFromData(slurp)
+}
+
+// UpdateTeam changes the name, description or visibility of a team.
+// The Organization of a team cannot be changed once it has been
+// created and trying to will return an error.
+func (c *Client) UpdateTeam(tdr *TeamDetailsRequest) (*Team, error) {
+	if tdr == nil {
+		return nil, errNilTeamDetailsRequest
+	}
+	teamID := strings.TrimSpace(tdr.TeamID)
+	if teamID == "" {
+		return nil, errEmptyTeamID
+	}
+	if tdr.Organization != "" {
+		return nil, errImmutableOrganization
+	}
+	if err := validateRichText(tdr.HTMLDescription); err != nil {
+		return nil, err
+	}
+	qs, err := otils.ToURLValues(tdr)
+	if err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/teams/%s", teamID)
+	slurp, err := c.doFormReq("PUT", path, qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutTeamFromData(slurp)
+}
+
+func parseOutTeamFromData(blob []byte) (*Team, error) {
+	tw := new(teamWrap)
+	if err := json.Unmarshal(blob, tw); err != nil {
+		return nil, err
+	}
+	return tw.Team, nil
+}
+
+// TeamMembership is the access that a user has to a team.
+type TeamMembership struct {
+	ID  int64  `json:"id,omitempty"`
+	GID string `json:"gid,omitempty"`
+
+	User *NamedAndIDdEntity `json:"user,omitempty"`
+	Team *NamedAndIDdEntity `json:"team,omitempty"`
+
+	IsAdmin         bool `json:"is_admin,omitempty"`
+	IsGuest         bool `json:"is_guest,omitempty"`
+	IsLimitedAccess bool `json:"is_limited_access,omitempty"`
 }
 
-type usersPager struct {
-	UsersPage
+type TeamMembershipsPage struct {
+	Memberships []*TeamMembership `json:"data"`
+	Err         error
+}
+
+type teamMembershipsPager struct {
+	TeamMembershipsPage
 
 	NextPage *pageToken `json:"next_page,omitempty"`
 }
 
-func (c *Client) ListAllUsersInTeam(teamID string) (pagesChan chan *UsersPage, cancelChan chan<- bool, err error) {
+func (c *Client) ListTeamMemberships(teamID string) (pagesChan chan *TeamMembershipsPage, cancelChan chan<- bool, err error) {
+	teamID = strings.TrimSpace(teamID)
 	if teamID == "" {
 		return nil, nil, errEmptyTeamID
 	}
 
-	cancelChan = make(chan bool, 1)
-	pagesChan = make(chan *UsersPage)
-
-	go func() {
-		defer close(pagesChan)
-
-		path := fmt.Sprintf("/teams/%s/users", teamID)
-		for {
-			fullURL := fmt.Sprintf("%s%s", baseURL, path)
-			req, err := http.NewRequest("GET", fullURL, nil)
-			if err != nil {
-				pagesChan <- &UsersPage{Err: err}
-				return
-			}
-			slurp, _, err := c.doAuthReqThenSlurpBody(req)
-			if err != nil {
-				pagesChan <- &UsersPage{Err: err}
-				return
-			}
-
-			pager := new(usersPager)
-			if err := json.Unmarshal(slurp, pager); err != nil {
-				pager.Err = err
-			}
-
-			usersPage := pager.UsersPage
-			pagesChan <- &usersPage
-
-			if np := pager.NextPage; np != nil && np.Path == "" {
-				path = np.Path
-			} else {
-				// End of this pagination
-				break
-			}
+	pagesChan = make(chan *TeamMembershipsPage)
+	path := fmt.Sprintf("/teams/%s/team_memberships?opt_fields=user.name,team.name,is_admin,is_guest,is_limited_access", teamID)
+	cancel := c.sendPages(pagesChan, path, func(slurp []byte) (interface{}, string, error) {
+		pager := new(teamMembershipsPager)
+		if err := json.Unmarshal(slurp, pager); err != nil {
+			return nil, "", err
 		}
-	}()
+		return &pager.TeamMembershipsPage, pager.NextPage.nextPath(), nil
+	})
+	return pagesChan, cancel, nil
+}
+
+func (c *Client) ListProjectsForTeam(teamID string) (pagesChan chan *ProjectsPage, cancelChan chan<- bool, err error) {
+	teamID = strings.TrimSpace(teamID)
+	if teamID == "" {
+		return nil, nil, errEmptyTeamID
+	}
 
-	return pagesChan, cancelChan, nil
+	startPath := fmt.Sprintf("/teams/%s/projects", teamID)
+	return c.pageForProjects(startPath)
 }
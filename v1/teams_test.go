@@ -0,0 +1,237 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestCreateAndUpdateTeam(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: teamsRoute})
+
+	tests := [...]struct {
+		update  bool
+		req     *asana.TeamDetailsRequest
+		want    *asana.Team
+		wantErr bool
+	}{
+		0: {req: nil, wantErr: true},
+		1: {req: &asana.TeamDetailsRequest{Name: "Engineering"}, wantErr: true},
+		2: {req: &asana.TeamDetailsRequest{Organization: workspaceID1}, wantErr: true},
+		3: {
+			req: &asana.TeamDetailsRequest{
+				Organization: workspaceID1, Name: "Engineering",
+				HTMLDescription: "<body><marquee>hi</marquee></body>",
+			},
+			wantErr: true,
+		},
+		4: {
+			req: &asana.TeamDetailsRequest{
+				Organization: workspaceID1, Name: "Engineering",
+				Description: "Builders", Visibility: asana.TeamRequestToJoin,
+			},
+			want: &asana.Team{
+				GID: teamID1, Name: "Engineering", Description: "Builders",
+				Organization: &asana.NamedAndIDdEntity{GID: workspaceID1},
+				Visibility:   asana.TeamRequestToJoin,
+			},
+		},
+		5: {update: true, req: nil, wantErr: true},
+		6: {update: true, req: &asana.TeamDetailsRequest{Name: "Eng"}, wantErr: true},
+		7: {update: true, req: &asana.TeamDetailsRequest{TeamID: teamID1, Organization: workspaceID1}, wantErr: true},
+		8: {
+			update: true,
+			req:    &asana.TeamDetailsRequest{TeamID: teamID1, Visibility: asana.TeamSecret},
+			want:   &asana.Team{GID: teamID1, Visibility: asana.TeamSecret},
+		},
+	}
+
+	for i, tt := range tests {
+		var team *asana.Team
+		var err error
+		if tt.update {
+			team, err = client.UpdateTeam(tt.req)
+		} else {
+			team, err = client.CreateTeam(tt.req)
+		}
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(team, tt.want) {
+			t.Errorf("#%d:\ngot:  %s\nwant: %s", i, jsonMarshal(team), jsonMarshal(tt.want))
+		}
+	}
+}
+
+func TestListTeamMemberships(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: teamsRoute})
+
+	pagesChan, _, err := client.ListTeamMemberships(teamID1)
+	if err != nil {
+		t.Fatalf("listing memberships: %v", err)
+	}
+	var admins, guests []string
+	for page := range pagesChan {
+		if page.Err != nil {
+			t.Fatalf("page err: %v", page.Err)
+		}
+		for _, membership := range page.Memberships {
+			if membership.IsAdmin {
+				admins = append(admins, membership.User.Name)
+			}
+			if membership.IsGuest {
+				guests = append(guests, membership.User.Name)
+			}
+		}
+	}
+	if want := []string{"Emeka"}; !reflect.DeepEqual(admins, want) {
+		t.Errorf("gotAdmins=%v wantAdmins=%v", admins, want)
+	}
+	if want := []string{"Contractor"}; !reflect.DeepEqual(guests, want) {
+		t.Errorf("gotGuests=%v wantGuests=%v", guests, want)
+	}
+}
+
+func TestListAllTeamsInOrganization(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: teamsRoute})
+
+	pagesChan, _, err := client.ListAllTeamsInOrganization(workspaceID1)
+	if err != nil {
+		t.Fatalf("listing teams: %v", err)
+	}
+	var gotGIDs []string
+	for page := range pagesChan {
+		if page.Err != nil {
+			t.Fatalf("page err: %v", page.Err)
+		}
+		for _, team := range page.Teams {
+			gotGIDs = append(gotGIDs, team.GID)
+		}
+	}
+	if want := []string{teamID1, "team-2"}; !reflect.DeepEqual(gotGIDs, want) {
+		t.Errorf("gotGIDs=%v wantGIDs=%v", gotGIDs, want)
+	}
+}
+
+func TestListProjectsForTeam(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: teamsRoute})
+
+	if _, _, err := client.ListProjectsForTeam(" "); err == nil {
+		t.Errorf("expected an error for a blank teamID")
+	}
+
+	pagesChan, _, err := client.ListProjectsForTeam(teamID1)
+	if err != nil {
+		t.Fatalf("listing projects: %v", err)
+	}
+	var gotGIDs []string
+	for page := range pagesChan {
+		if page.Err != nil {
+			t.Fatalf("page err: %v", page.Err)
+		}
+		for _, project := range page.Projects {
+			gotGIDs = append(gotGIDs, project.GID)
+		}
+	}
+	if want := []string{"project-1", "project-2"}; !reflect.DeepEqual(gotGIDs, want) {
+		t.Errorf("gotGIDs=%v wantGIDs=%v", gotGIDs, want)
+	}
+}
+
+func (b *backend) teamsRoundTrip(req *http.Request) (*http.Response, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/api/1.0")
+	switch path {
+	case "/teams", "/teams/" + teamID1:
+		wantMethod := "POST"
+		if path != "/teams" {
+			wantMethod = "PUT"
+		}
+		if badAuthResp, err := b.checkAuthorization(req, wantMethod); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		if err := req.ParseForm(); err != nil {
+			return makeResp(err.Error(), http.StatusBadRequest, nil), nil
+		}
+		team := &asana.Team{
+			GID:         teamID1,
+			Name:        req.PostForm.Get("name"),
+			Description: req.PostForm.Get("description"),
+			Visibility:  asana.TeamVisibility(req.PostForm.Get("visibility")),
+		}
+		if org := req.PostForm.Get("organization"); org != "" {
+			team.Organization = &asana.NamedAndIDdEntity{GID: org}
+		}
+		body := fmt.Sprintf(`{"data": %s}`, jsonMarshal(team))
+		return makeResp("200 OK", http.StatusOK, nopCloser(body)), nil
+
+	case "/teams/" + teamID1 + "/team_memberships":
+		if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		return makeRespFromFile(fmt.Sprintf("./testdata/team-memberships-%s.json", teamID1))
+
+	case "/teams/" + teamID1 + "/projects":
+		if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		page := "1"
+		if req.URL.Query().Get("offset") == "page-2" {
+			page = "2"
+		}
+		return makeRespFromFile(fmt.Sprintf("./testdata/projects-%s-page-%s.json", teamID1, page))
+
+	case "/organizations/" + workspaceID1 + "/teams":
+		if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		body := `{"data": [{"gid": "team-1"}], "next_page": {"offset": "page-2", "path": "/organizations/workspace-1/teams?offset=page-2"}}`
+		if req.URL.Query().Get("offset") == "page-2" {
+			body = `{"data": [{"gid": "team-2"}], "next_page": null}`
+		}
+		return makeResp("200 OK", http.StatusOK, nopCloser(body)), nil
+
+	default:
+		return unknownRouteResp, nil
+	}
+}
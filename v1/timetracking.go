@@ -0,0 +1,347 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/orijtech/otils"
+)
+
+// TimeTrackingEntry is a block of time logged against a task.
+type TimeTrackingEntry struct {
+	GID string `json:"gid,omitempty"`
+
+	DurationMinutes int `json:"duration_minutes,omitempty"`
+
+	// EnteredOn is the day that the time was spent on.
+	EnteredOn *Date `json:"entered_on,omitempty"`
+
+	CreatedBy *NamedAndIDdEntity `json:"created_by,omitempty"`
+	CreatedAt *time.Time         `json:"created_at,omitempty"`
+
+	Task *NamedAndIDdEntity `json:"task,omitempty"`
+}
+
+// Duration returns the tracked time as a time.Duration.
+func (tte *TimeTrackingEntry) Duration() time.Duration {
+	if tte == nil {
+		return 0
+	}
+	return time.Duration(tte.DurationMinutes) * time.Minute
+}
+
+type TimeTrackingEntryRequest struct {
+	// EntryID is the gid of the entry to update.
+	EntryID string `json:"-"`
+
+	DurationMinutes int `json:"duration_minutes,omitempty"`
+
+	// EnteredOn defaults to today when creating an entry.
+	EnteredOn *Date `json:"entered_on,omitempty"`
+}
+
+var (
+	errNilTimeTrackingRequest  = errors.New("expecting a non-nil timeTrackingEntryRequest")
+	errEmptyTimeTrackingID     = errors.New("expecting a non-empty timeTrackingEntryID")
+	errNonPositiveDuration     = errors.New("expecting a positive durationMinutes")
+	errNilTimeTrackingQuery    = errors.New("expecting a non-nil timeTrackingQuery")
+	errTimeTrackingSinceAfter  = errors.New("since cannot be after until")
+	errInvalidTimeTrackingDate = errors.New("expecting a valid enteredOn date")
+)
+
+func (treq *TimeTrackingEntryRequest) Validate() error {
+	if treq == nil {
+		return errNilTimeTrackingRequest
+	}
+	if treq.DurationMinutes <= 0 {
+		return errNonPositiveDuration
+	}
+	return treq.validate()
+}
+
+func (treq *TimeTrackingEntryRequest) validate() error {
+	if treq.EnteredOn != nil && !treq.EnteredOn.IsValid() {
+		return errInvalidTimeTrackingDate
+	}
+	return nil
+}
+
+type timeTrackingEntryWrap struct {
+	TimeTrackingEntry *TimeTrackingEntry `json:"data"`
+}
+
+func parseOutTimeTrackingEntryFromData(blob []byte) (*TimeTrackingEntry, error) {
+	wrap := new(timeTrackingEntryWrap)
+	if err := json.Unmarshal(blob, wrap); err != nil {
+		return nil, err
+	}
+	return wrap.TimeTrackingEntry, nil
+}
+
+// CreateTimeTrackingEntry logs time against the task.
+func (c *Client) CreateTimeTrackingEntry(taskID string, treq *TimeTrackingEntryRequest) (*TimeTrackingEntry, error) {
+	taskID = strings.TrimSpace(taskID)
+	if taskID == "" {
+		return nil, errEmptyTaskID
+	}
+	if err := treq.Validate(); err != nil {
+		return nil, err
+	}
+	qs, err := otils.ToURLValues(treq)
+	if err != nil {
+		return nil, err
+	}
+	slurp, err := c.doFormReq("POST", fmt.Sprintf("/tasks/%s/time_tracking_entries", taskID), qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutTimeTrackingEntryFromData(slurp)
+}
+
+// UpdateTimeTrackingEntry changes the duration or the
+// date of an entry. Fields left blank are unchanged.
+func (c *Client) UpdateTimeTrackingEntry(treq *TimeTrackingEntryRequest) (*TimeTrackingEntry, error) {
+	if treq == nil {
+		return nil, errNilTimeTrackingRequest
+	}
+	entryID := strings.TrimSpace(treq.EntryID)
+	if entryID == "" {
+		return nil, errEmptyTimeTrackingID
+	}
+	if treq.DurationMinutes < 0 {
+		return nil, errNonPositiveDuration
+	}
+	if err := treq.validate(); err != nil {
+		return nil, err
+	}
+	qs, err := otils.ToURLValues(treq)
+	if err != nil {
+		return nil, err
+	}
+	slurp, err := c.doFormReq("PUT", fmt.Sprintf("/time_tracking_entries/%s", entryID), qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutTimeTrackingEntryFromData(slurp)
+}
+
+func (c *Client) FindTimeTrackingEntryByID(entryID string) (*TimeTrackingEntry, error) {
+	entryID = strings.TrimSpace(entryID)
+	if entryID == "" {
+		return nil, errEmptyTimeTrackingID
+	}
+	fullURL := fmt.Sprintf("%s/time_tracking_entries/%s", baseURL, entryID)
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutTimeTrackingEntryFromData(slurp)
+}
+
+func (c *Client) DeleteTimeTrackingEntry(entryID string) error {
+	entryID = strings.TrimSpace(entryID)
+	if entryID == "" {
+		return errEmptyTimeTrackingID
+	}
+	fullURL := fmt.Sprintf("%s/time_tracking_entries/%s", baseURL, entryID)
+	req, err := http.NewRequest("DELETE", fullURL, nil)
+	if err != nil {
+		return err
+	}
+	_, _, err = c.doAuthReqThenSlurpBody(req)
+	return err
+}
+
+type TimeTrackingEntriesPage struct {
+	TimeTrackingEntries []*TimeTrackingEntry `json:"data"`
+	Err                 error
+}
+
+type timeTrackingEntriesPager struct {
+	TimeTrackingEntriesPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+// ListTimeTrackingEntriesForTask pages through the time logged against a task.
+func (c *Client) ListTimeTrackingEntriesForTask(taskID string) (pagesChan chan *TimeTrackingEntriesPage, cancelChan chan<- bool, err error) {
+	taskID = strings.TrimSpace(taskID)
+	if taskID == "" {
+		return nil, nil, errEmptyTaskID
+	}
+
+	pagesChan = make(chan *TimeTrackingEntriesPage)
+	path := fmt.Sprintf("/tasks/%s/time_tracking_entries", taskID)
+	cancel := c.sendPages(pagesChan, path, func(slurp []byte) (interface{}, string, error) {
+		pager := new(timeTrackingEntriesPager)
+		if err := json.Unmarshal(slurp, pager); err != nil {
+			return nil, "", err
+		}
+		return &pager.TimeTrackingEntriesPage, pager.NextPage.nextPath(), nil
+	})
+	return pagesChan, cancel, nil
+}
+
+// TimeTrackingQuery selects the entries that SummarizeTimeTracking adds up.
+type TimeTrackingQuery struct {
+	// ProjectID is required.
+	ProjectID string
+
+	// Since and Until if set, bound the EnteredOn
+	// dates of the entries. Both are inclusive.
+	Since *Date
+	Until *Date
+
+	// UserIDs if set, only counts the entries
+	// created by the users with these gids.
+	UserIDs []string
+}
+
+func (ttq *TimeTrackingQuery) Validate() error {
+	if ttq == nil {
+		return errNilTimeTrackingQuery
+	}
+	if strings.TrimSpace(ttq.ProjectID) == "" {
+		return errEmptyProjectID
+	}
+	if ttq.Since != nil && ttq.Until != nil && ttq.Since.After(*ttq.Until) {
+		return errTimeTrackingSinceAfter
+	}
+	return nil
+}
+
+func (ttq *TimeTrackingQuery) matches(tte *TimeTrackingEntry) bool {
+	if ttq.Since != nil || ttq.Until != nil {
+		if tte.EnteredOn == nil {
+			return false
+		}
+		if ttq.Since != nil && tte.EnteredOn.Before(*ttq.Since) {
+			return false
+		}
+		if ttq.Until != nil && tte.EnteredOn.After(*ttq.Until) {
+			return false
+		}
+	}
+	if len(ttq.UserIDs) == 0 {
+		return true
+	}
+	if tte.CreatedBy == nil {
+		return false
+	}
+	for _, userID := range ttq.UserIDs {
+		if userID == tte.CreatedBy.GID {
+			return true
+		}
+	}
+	return false
+}
+
+// TimeTrackingSummary is the tracked time of a project in minutes,
+// in total and broken down by user gid, task gid and entry date.
+type TimeTrackingSummary struct {
+	ProjectID    string
+	TotalMinutes int
+
+	ByUser map[string]int
+	ByTask map[string]int
+	ByDate map[Date]int
+}
+
+// Total returns TotalMinutes as a time.Duration.
+func (tts *TimeTrackingSummary) Total() time.Duration {
+	return time.Duration(tts.TotalMinutes) * time.Minute
+}
+
+func (tts *TimeTrackingSummary) add(taskID string, tte *TimeTrackingEntry) {
+	tts.TotalMinutes += tte.DurationMinutes
+	tts.ByTask[taskID] += tte.DurationMinutes
+	if tte.CreatedBy != nil {
+		tts.ByUser[tte.CreatedBy.GID] += tte.DurationMinutes
+	}
+	if tte.EnteredOn != nil {
+		tts.ByDate[*tte.EnteredOn] += tte.DurationMinutes
+	}
+}
+
+// SummarizeTimeTracking adds up the time tracked on every task of
+// a project that matches the query.
+//
+// Asana only lists time tracking entries per task, so besides paging
+// through the tasks of the project, it makes one request per task,
+// one after the other, which can take a while for large projects.
+// Only the tasks that are in the project are counted: time tracked
+// on their subtasks is not included unless the subtasks were also
+// added to the project.
+func (c *Client) SummarizeTimeTracking(ttq *TimeTrackingQuery) (*TimeTrackingSummary, error) {
+	if err := ttq.Validate(); err != nil {
+		return nil, err
+	}
+	tasksChan, _, err := c.TasksForProject(ttq.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &TimeTrackingSummary{
+		ProjectID: ttq.ProjectID,
+		ByUser:    make(map[string]int),
+		ByTask:    make(map[string]int),
+		ByDate:    make(map[Date]int),
+	}
+
+	var taskIDs []string
+	for page := range tasksChan {
+		if err := page.Err; err != nil {
+			return nil, err
+		}
+		for _, task := range page.Tasks {
+			taskID := task.GID
+			if taskID == "" && task.ID != 0 {
+				taskID = strconv.FormatInt(task.ID, 10)
+			}
+			if taskID != "" {
+				taskIDs = append(taskIDs, taskID)
+			}
+		}
+	}
+
+	for _, taskID := range taskIDs {
+		entriesChan, _, err := c.ListTimeTrackingEntriesForTask(taskID)
+		if err != nil {
+			return nil, err
+		}
+		for page := range entriesChan {
+			if err := page.Err; err != nil {
+				return nil, err
+			}
+			for _, entry := range page.TimeTrackingEntries {
+				if entry != nil && ttq.matches(entry) {
+					summary.add(taskID, entry)
+				}
+			}
+		}
+	}
+	return summary, nil
+}
@@ -0,0 +1,208 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestCreateTimeTrackingEntry(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: timeTrackingRoute})
+
+	enteredOn := asana.Date{Year: 2017, Month: time.September, Day: 4}
+	tests := [...]struct {
+		taskID  string
+		req     *asana.TimeTrackingEntryRequest
+		wantErr bool
+	}{
+		0: {taskID: taskID1, req: nil, wantErr: true},
+		1: {taskID: " ", req: &asana.TimeTrackingEntryRequest{DurationMinutes: 30}, wantErr: true},
+		2: {taskID: taskID1, req: &asana.TimeTrackingEntryRequest{DurationMinutes: 0}, wantErr: true},
+		3: {taskID: taskID1, req: &asana.TimeTrackingEntryRequest{DurationMinutes: 30, EnteredOn: &asana.Date{Year: 2017, Month: 13, Day: 1}}, wantErr: true},
+		4: {taskID: taskID1, req: &asana.TimeTrackingEntryRequest{DurationMinutes: 90, EnteredOn: &enteredOn}},
+		5: {taskID: taskID1, req: &asana.TimeTrackingEntryRequest{DurationMinutes: -5}, wantErr: true},
+	}
+
+	for i, tt := range tests {
+		entry, err := client.CreateTimeTrackingEntry(tt.taskID, tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		if got, want := entry.Duration(), time.Duration(tt.req.DurationMinutes)*time.Minute; got != want {
+			t.Errorf("#%d: gotDuration=%v wantDuration=%v", i, got, want)
+		}
+		if entry.EnteredOn == nil || *entry.EnteredOn != *tt.req.EnteredOn {
+			t.Errorf("#%d: gotEnteredOn=%v wantEnteredOn=%v", i, entry.EnteredOn, tt.req.EnteredOn)
+		}
+	}
+
+	var noEntry *asana.TimeTrackingEntry
+	if got := noEntry.Duration(); got != 0 {
+		t.Errorf("nil entry: gotDuration=%v", got)
+	}
+}
+
+func TestSummarizeTimeTracking(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: timeTrackingRoute})
+
+	sep4 := asana.Date{Year: 2017, Month: time.September, Day: 4}
+	sep5 := asana.Date{Year: 2017, Month: time.September, Day: 5}
+	sep6 := asana.Date{Year: 2017, Month: time.September, Day: 6}
+
+	tests := [...]struct {
+		query   *asana.TimeTrackingQuery
+		want    *asana.TimeTrackingSummary
+		wantErr bool
+	}{
+		0: {query: nil, wantErr: true},
+		1: {query: &asana.TimeTrackingQuery{}, wantErr: true},
+		2: {query: &asana.TimeTrackingQuery{ProjectID: projectID1, Since: &sep6, Until: &sep4}, wantErr: true},
+		3: {
+			query: &asana.TimeTrackingQuery{ProjectID: projectID1},
+			want: &asana.TimeTrackingSummary{
+				ProjectID:    projectID1,
+				TotalMinutes: 210,
+				ByUser:       map[string]int{"user-1": 150, "user-2": 60},
+				ByTask:       map[string]int{"task-a": 90, "task-b": 120},
+				ByDate:       map[asana.Date]int{sep4: 30, sep5: 120, sep6: 60},
+			},
+		},
+		4: {
+			query: &asana.TimeTrackingQuery{ProjectID: projectID1, Since: &sep5, Until: &sep5},
+			want: &asana.TimeTrackingSummary{
+				ProjectID:    projectID1,
+				TotalMinutes: 120,
+				ByUser:       map[string]int{"user-1": 120},
+				ByTask:       map[string]int{"task-a": 60, "task-b": 60},
+				ByDate:       map[asana.Date]int{sep5: 120},
+			},
+		},
+		5: {
+			query: &asana.TimeTrackingQuery{ProjectID: projectID1, UserIDs: []string{"user-2"}},
+			want: &asana.TimeTrackingSummary{
+				ProjectID:    projectID1,
+				TotalMinutes: 60,
+				ByUser:       map[string]int{"user-2": 60},
+				ByTask:       map[string]int{"task-b": 60},
+				ByDate:       map[asana.Date]int{sep6: 60},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		summary, err := client.SummarizeTimeTracking(tt.query)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(summary, tt.want) {
+			t.Errorf("#%d:\ngot:  %+v\nwant: %+v", i, summary, tt.want)
+		}
+	}
+}
+
+func (b *backend) timeTrackingRoundTrip(req *http.Request) (*http.Response, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/api/1.0")
+	if req.Method == "POST" {
+		return b.createTimeTrackingEntryRoundTrip(path, req)
+	}
+	if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
+		return badAuthResp, err
+	}
+
+	var body string
+	switch path {
+	case "/projects/" + projectID1 + "/tasks":
+		body = `{"data": [{"gid": "task-a", "name": "Discovery"}],
+			"next_page": {"offset": "page-2", "path": "/projects/project-1/tasks?offset=page-2"}}`
+		if req.URL.Query().Get("offset") == "page-2" {
+			body = `{"data": [{"gid": "task-b", "name": "Workshop"}], "next_page": null}`
+		}
+
+	case "/tasks/task-a/time_tracking_entries":
+		body = `{"data": [
+			{"gid": "tte-1", "duration_minutes": 30, "entered_on": "2017-09-04", "created_by": {"gid": "user-1"}},
+			{"gid": "tte-2", "duration_minutes": 60, "entered_on": "2017-09-05", "created_by": {"gid": "user-1"}}
+		]}`
+
+	case "/tasks/task-b/time_tracking_entries":
+		body = `{"data": [{"gid": "tte-3", "duration_minutes": 60, "entered_on": "2017-09-05", "created_by": {"gid": "user-1"}}],
+			"next_page": {"offset": "page-2", "path": "/tasks/task-b/time_tracking_entries?offset=page-2"}}`
+		if req.URL.Query().Get("offset") == "page-2" {
+			body = `{"data": [{"gid": "tte-4", "duration_minutes": 60, "entered_on": "2017-09-06", "created_by": {"gid": "user-2"}}]}`
+		}
+
+	default:
+		return unknownRouteResp, nil
+	}
+	return makeResp("200 OK", http.StatusOK, nopCloser(body)), nil
+}
+
+func (b *backend) createTimeTrackingEntryRoundTrip(path string, req *http.Request) (*http.Response, error) {
+	if badAuthResp, err := b.checkAuthorization(req, "POST"); err != nil || badAuthResp != nil {
+		return badAuthResp, err
+	}
+	if path != "/tasks/"+taskID1+"/time_tracking_entries" {
+		return unknownRouteResp, nil
+	}
+	if err := req.ParseForm(); err != nil {
+		return makeResp(err.Error(), http.StatusBadRequest, nil), nil
+	}
+	minutes, err := strconv.Atoi(req.PostForm.Get("duration_minutes"))
+	if err != nil || minutes <= 0 {
+		return makeResp("invalid duration_minutes", http.StatusBadRequest, nil), nil
+	}
+	enteredOn, err := asana.ParseDate(req.PostForm.Get("entered_on"))
+	if err != nil {
+		return makeResp(err.Error(), http.StatusBadRequest, nil), nil
+	}
+	entry := &asana.TimeTrackingEntry{
+		GID:             "tte-5",
+		DurationMinutes: minutes,
+		EnteredOn:       &enteredOn,
+		CreatedBy:       &asana.NamedAndIDdEntity{GID: "user-1"},
+		Task:            &asana.NamedAndIDdEntity{GID: taskID1},
+	}
+	return makeResp("200 OK", http.StatusOK, nopCloser(fmt.Sprintf(`{"data": %s}`, jsonMarshal(entry)))), nil
+}
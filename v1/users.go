@@ -0,0 +1,129 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type User struct {
+	ID    int64  `json:"id,omitempty"`
+	GID   string `json:"gid,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+
+	Photo *Photo `json:"photo,omitempty"`
+
+	// Workspaces are the workspaces and organizations that
+	// the user is a member of and that are visible to you.
+	Workspaces []*NamedAndIDdEntity `json:"workspaces,omitempty"`
+}
+
+// Photo holds the URLs of a user's profile photo in various sizes.
+type Photo struct {
+	Image21x21     string `json:"image_21x21,omitempty"`
+	Image27x27     string `json:"image_27x27,omitempty"`
+	Image36x36     string `json:"image_36x36,omitempty"`
+	Image60x60     string `json:"image_60x60,omitempty"`
+	Image128x128   string `json:"image_128x128,omitempty"`
+	Image1024x1024 string `json:"image_1024x1024,omitempty"`
+}
+
+// userOptFields are requested when listing users since
+// listings otherwise only include their names and gids.
+const userOptFields = "name,email,photo,workspaces,workspaces.name"
+
+type userWrap struct {
+	User *User `json:"data"`
+}
+
+func parseOutUserFromData(blob []byte) (*User, error) {
+	uw := new(userWrap)
+	if err := json.Unmarshal(blob, uw); err != nil {
+		return nil, err
+	}
+	return uw.User, nil
+}
+
+// Me returns the user that the client is authenticated as.
+func (c *Client) Me() (*User, error) {
+	return c.FindUserByID(MeAsUser)
+}
+
+// FindUserByID returns the user identified by userID, which can
+// be the user's gid, email address or "me" for the current user.
+func (c *Client) FindUserByID(userID string) (*User, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, errEmptyUserID
+	}
+	fullURL := fmt.Sprintf("%s/users/%s", baseURL, url.PathEscape(userID))
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutUserFromData(slurp)
+}
+
+type UsersPage struct {
+	Users []*User `json:"data"`
+	Err   error
+}
+
+type usersPager struct {
+	UsersPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+func (c *Client) ListUsersInWorkspace(workspaceID string) (pagesChan chan *UsersPage, cancelChan chan<- bool, err error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" {
+		return nil, nil, errEmptyWorkspace
+	}
+
+	startPath := fmt.Sprintf("/workspaces/%s/users?opt_fields=%s", workspaceID, userOptFields)
+	return c.pageForUsers(startPath)
+}
+
+func (c *Client) ListUsersInTeam(teamID string) (pagesChan chan *UsersPage, cancelChan chan<- bool, err error) {
+	teamID = strings.TrimSpace(teamID)
+	if teamID == "" {
+		return nil, nil, errEmptyTeamID
+	}
+
+	startPath := fmt.Sprintf("/teams/%s/users?opt_fields=%s", teamID, userOptFields)
+	return c.pageForUsers(startPath)
+}
+
+func (c *Client) pageForUsers(path string) (pagesChan chan *UsersPage, cancelChan chan<- bool, err error) {
+	pagesChan = make(chan *UsersPage)
+	cancel := c.sendPages(pagesChan, path, func(slurp []byte) (interface{}, string, error) {
+		pager := new(usersPager)
+		if err := json.Unmarshal(slurp, pager); err != nil {
+			return nil, "", err
+		}
+		return &pager.UsersPage, pager.NextPage.nextPath(), nil
+	})
+	return pagesChan, cancel, nil
+}
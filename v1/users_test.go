@@ -0,0 +1,138 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestFindUserByID(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: usersRoute})
+
+	tests := [...]struct {
+		userID    string
+		wantGID   string
+		wantEmail string
+		wantErr   bool
+	}{
+		0: {userID: "", wantErr: true},
+		1: {userID: "   ", wantErr: true},
+		2: {userID: "me", wantGID: "7", wantEmail: "emeka@orijtech.com"},
+		3: {userID: "8", wantGID: "8", wantEmail: "ada@orijtech.com"},
+		4: {userID: " ada@orijtech.com ", wantGID: "8", wantEmail: "ada@orijtech.com"},
+		5: {userID: "unknown@orijtech.com", wantErr: true},
+	}
+
+	for i, tt := range tests {
+		user, err := client.FindUserByID(tt.userID)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		if user.GID != tt.wantGID || user.Email != tt.wantEmail {
+			t.Errorf("#%d: got gid=%q email=%q want gid=%q email=%q", i, user.GID, user.Email, tt.wantGID, tt.wantEmail)
+		}
+	}
+
+	me, err := client.Me()
+	if err != nil {
+		t.Fatalf("me: %v", err)
+	}
+	if me.GID != "7" {
+		t.Errorf("me: gotGID=%q wantGID=%q", me.GID, "7")
+	}
+}
+
+func TestListUsersInWorkspace(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: usersRoute})
+
+	if _, _, err := client.ListUsersInWorkspace(" "); err == nil {
+		t.Errorf("expected an error for a blank workspace")
+	}
+
+	pagesChan, _, err := client.ListUsersInWorkspace(workspaceID1)
+	if err != nil {
+		t.Fatalf("listing users: %v", err)
+	}
+	var gotEmails []string
+	for page := range pagesChan {
+		if page.Err != nil {
+			t.Fatalf("page err: %v", page.Err)
+		}
+		for _, user := range page.Users {
+			gotEmails = append(gotEmails, user.Email)
+			if len(user.Workspaces) != 1 || user.Workspaces[0].Name != "orijtech" {
+				t.Errorf("%s: gotWorkspaces=%#v", user.Name, user.Workspaces)
+			}
+		}
+	}
+	wantEmails := []string{"emeka@orijtech.com", "ada@orijtech.com"}
+	if !reflect.DeepEqual(gotEmails, wantEmails) {
+		t.Errorf("gotEmails=%v wantEmails=%v", gotEmails, wantEmails)
+	}
+}
+
+var usersByID = map[string]*asana.User{
+	"me":                 {GID: "7", Name: "Emeka", Email: "emeka@orijtech.com"},
+	"7":                  {GID: "7", Name: "Emeka", Email: "emeka@orijtech.com"},
+	"8":                  {GID: "8", Name: "Ada", Email: "ada@orijtech.com"},
+	"ada@orijtech.com":   {GID: "8", Name: "Ada", Email: "ada@orijtech.com"},
+	"emeka@orijtech.com": {GID: "7", Name: "Emeka", Email: "emeka@orijtech.com"},
+}
+
+func (b *backend) usersRoundTrip(req *http.Request) (*http.Response, error) {
+	if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
+		return badAuthResp, err
+	}
+
+	if strings.HasSuffix(req.URL.Path, "/workspaces/"+workspaceID1+"/users") {
+		if got, want := req.URL.Query().Get("opt_fields"), "email"; !strings.Contains(got, want) {
+			return makeResp("expected the emails to be requested", http.StatusBadRequest, nil), nil
+		}
+		page := "1"
+		if req.URL.Query().Get("offset") == "page-2" {
+			page = "2"
+		}
+		return makeRespFromFile(fmt.Sprintf("./testdata/users-%s-page-%s.json", workspaceID1, page))
+	}
+
+	userID := strings.TrimPrefix(req.URL.Path, "/api/1.0/users/")
+	user, ok := usersByID[userID]
+	if !ok {
+		return makeResp("user not found", http.StatusNotFound, nil), nil
+	}
+	body := fmt.Sprintf(`{"data": %s}`, jsonMarshal(user))
+	return makeResp("200 OK", http.StatusOK, nopCloser(body)), nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// UserTaskList is the My Tasks list of a user in a workspace.
+// Its sections are regular sections and tasks can be moved between
+// them with AddTaskToSection or TaskRequest.AssigneeSection.
+type UserTaskList struct {
+	ID   int64  `json:"id,omitempty"`
+	GID  string `json:"gid,omitempty"`
+	Name string `json:"name,omitempty"`
+
+	Owner     *NamedAndIDdEntity `json:"owner,omitempty"`
+	Workspace *NamedAndIDdEntity `json:"workspace,omitempty"`
+}
+
+var errEmptyUserTaskListID = errors.New("expecting a non-empty userTaskListID")
+
+type userTaskListWrap struct {
+	UserTaskList *UserTaskList `json:"data"`
+}
+
+func parseOutUserTaskListFromData(blob []byte) (*UserTaskList, error) {
+	uw := new(userTaskListWrap)
+	if err := json.Unmarshal(blob, uw); err != nil {
+		return nil, err
+	}
+	return uw.UserTaskList, nil
+}
+
+// FindUserTaskListForUser returns the My Tasks list of the user,
+// identified by gid, email address or "me", in the workspace.
+func (c *Client) FindUserTaskListForUser(userID, workspaceID string) (*UserTaskList, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, errEmptyUserID
+	}
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" {
+		return nil, errEmptyWorkspace
+	}
+	qs := url.Values{"workspace": {workspaceID}}
+	fullURL := fmt.Sprintf("%s/users/%s/user_task_list?%s", baseURL, url.PathEscape(userID), qs.Encode())
+	return c.findUserTaskList(fullURL)
+}
+
+func (c *Client) FindUserTaskListByID(userTaskListID string) (*UserTaskList, error) {
+	userTaskListID = strings.TrimSpace(userTaskListID)
+	if userTaskListID == "" {
+		return nil, errEmptyUserTaskListID
+	}
+	fullURL := fmt.Sprintf("%s/user_task_lists/%s", baseURL, userTaskListID)
+	return c.findUserTaskList(fullURL)
+}
+
+func (c *Client) findUserTaskList(fullURL string) (*UserTaskList, error) {
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutUserTaskListFromData(slurp)
+}
+
+// UserTaskListQuery filters the tasks listed by TasksForUserTaskList.
+type UserTaskListQuery struct {
+	// CompletedSince if set, only lists the incomplete
+	// tasks and those completed after CompletedSince.
+	CompletedSince *time.Time `json:"completed_since,omitempty"`
+
+	// IncompleteOnly if set, only lists the incomplete tasks.
+	IncompleteOnly bool `json:"-"`
+
+	Limit int `json:"limit,omitempty"`
+
+	// OptFields are the extra fields of the tasks to return,
+	// for example "assignee_section" or "due_on".
+	OptFields []string `json:"-"`
+}
+
+// TasksForUserTaskList pages through the tasks in a My Tasks list,
+// including completed ones unless they are filtered out by uq.
+func (c *Client) TasksForUserTaskList(userTaskListID string, uq *UserTaskListQuery) (resultsChan chan *TaskResultPage, cancelChan chan<- bool, err error) {
+	userTaskListID = strings.TrimSpace(userTaskListID)
+	if userTaskListID == "" {
+		return nil, nil, errEmptyUserTaskListID
+	}
+
+	tq := new(TaskQuery)
+	if uq != nil {
+		tq.CompletedSince = uq.CompletedSince
+		tq.IncompleteOnly = uq.IncompleteOnly
+		tq.Limit = uq.Limit
+		tq.OptFields = uq.OptFields
+	}
+	qs, err := tq.toURLValues()
+	if err != nil {
+		return nil, nil, err
+	}
+	startPath := fmt.Sprintf("/user_task_lists/%s/tasks?%s", userTaskListID, qs.Encode())
+	return c.doTasksPaging(startPath)
+}
+
+// ListSectionsForUserTaskList pages through the sections of a My Tasks list.
+func (c *Client) ListSectionsForUserTaskList(userTaskListID string) (pagesChan chan *SectionsPage, cancelChan chan<- bool, err error) {
+	userTaskListID = strings.TrimSpace(userTaskListID)
+	if userTaskListID == "" {
+		return nil, nil, errEmptyUserTaskListID
+	}
+	// The sections of a My Tasks list are listed as though it were a project.
+	return c.ListSectionsForProject(userTaskListID)
+}
@@ -0,0 +1,153 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orijtech/asana/v1"
+)
+
+const userTaskListID1 = "utl-1"
+
+func TestFindUserTaskListForUser(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: userTaskListsRoute})
+
+	tests := [...]struct {
+		userID      string
+		workspaceID string
+		wantErr     bool
+	}{
+		0: {userID: "", workspaceID: workspaceID1, wantErr: true},
+		1: {userID: "me", workspaceID: "  ", wantErr: true},
+		2: {userID: "me", workspaceID: "workspace-2", wantErr: true},
+		3: {userID: "me", workspaceID: workspaceID1},
+	}
+
+	for i, tt := range tests {
+		utl, err := client.FindUserTaskListForUser(tt.userID, tt.workspaceID)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: wanted non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		if utl.GID != userTaskListID1 {
+			t.Errorf("#%d: gotGID=%q wantGID=%q", i, utl.GID, userTaskListID1)
+		}
+	}
+}
+
+func TestTasksForUserTaskList(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: userTaskListsRoute})
+
+	since := time.Date(2017, time.August, 1, 0, 0, 0, 0, time.UTC)
+	tests := [...]struct {
+		query     *asana.UserTaskListQuery
+		wantTasks []string
+	}{
+		// All the tasks, including completed ones.
+		0: {query: nil, wantTasks: []string{"Write docs:Recently assigned", "Ship it:Today", "Old task:Later"}},
+		1: {
+			query:     &asana.UserTaskListQuery{CompletedSince: &since},
+			wantTasks: []string{"Write docs:Recently assigned", "Ship it:Today"},
+		},
+		2: {
+			query:     &asana.UserTaskListQuery{IncompleteOnly: true, OptFields: []string{"assignee_section.name"}},
+			wantTasks: []string{"Write docs:Recently assigned"},
+		},
+	}
+
+	for i, tt := range tests {
+		resultsChan, _, err := client.TasksForUserTaskList(userTaskListID1, tt.query)
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		var gotTasks []string
+		for page := range resultsChan {
+			if page.Err != nil {
+				t.Errorf("#%d: page err: %v", i, page.Err)
+				break
+			}
+			for _, task := range page.Tasks {
+				section := ""
+				if task.AssigneeSection != nil {
+					section = task.AssigneeSection.Name
+				}
+				gotTasks = append(gotTasks, task.Name+":"+section)
+			}
+		}
+		if !reflect.DeepEqual(gotTasks, tt.wantTasks) {
+			t.Errorf("#%d: gotTasks=%v wantTasks=%v", i, gotTasks, tt.wantTasks)
+		}
+	}
+}
+
+func (b *backend) userTaskListsRoundTrip(req *http.Request) (*http.Response, error) {
+	if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
+		return badAuthResp, err
+	}
+	query := req.URL.Query()
+
+	switch req.URL.Path {
+	case "/api/1.0/users/me/user_task_list":
+		if got := query.Get("workspace"); got != workspaceID1 {
+			return makeResp("no user task list in workspace: "+got, http.StatusNotFound, nil), nil
+		}
+		body := fmt.Sprintf(`{"data": {"gid": %q, "name": "My Tasks", "workspace": {"gid": %q}}}`, userTaskListID1, workspaceID1)
+		return makeResp("200 OK", http.StatusOK, nopCloser(body)), nil
+
+	case "/api/1.0/user_task_lists/" + userTaskListID1 + "/tasks":
+		tasks := []string{
+			`{"name": "Write docs", "completed": false, "assignee_section": {"gid": "s1", "name": "Recently assigned"}}`,
+		}
+		switch since := query.Get("completed_since"); since {
+		case "now":
+		case "":
+			tasks = append(tasks,
+				`{"name": "Ship it", "completed": true, "assignee_section": {"gid": "s2", "name": "Today"}}`,
+				`{"name": "Old task", "completed": true, "assignee_section": {"gid": "s3", "name": "Later"}}`,
+			)
+		default:
+			if _, err := time.Parse(time.RFC3339, since); err != nil {
+				return makeResp(err.Error(), http.StatusBadRequest, nil), nil
+			}
+			tasks = append(tasks, `{"name": "Ship it", "completed": true, "assignee_section": {"gid": "s2", "name": "Today"}}`)
+		}
+		body := fmt.Sprintf(`{"data": [%s], "next_page": null}`, strings.Join(tasks, ","))
+		return makeResp("200 OK", http.StatusOK, nopCloser(body)), nil
+
+	default:
+		return unknownRouteResp, nil
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/orijtech/otils"
+)
+
+type Webhook struct {
+	ID     int64  `json:"id,omitempty"`
+	GID    string `json:"gid,omitempty"`
+	Active bool   `json:"active,omitempty"`
+
+	// Resource is the object whose changes are delivered.
+	Resource *NamedAndIDdEntity `json:"resource,omitempty"`
+
+	// Target is the URL that events are delivered to.
+	Target string `json:"target,omitempty"`
+
+	Filters []*WebhookFilter `json:"filters,omitempty"`
+
+	CreatedAt          *time.Time `json:"created_at,omitempty"`
+	LastSuccessAt      *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt      *time.Time `json:"last_failure_at,omitempty"`
+	LastFailureContent string     `json:"last_failure_content,omitempty"`
+}
+
+// WebhookFilter restricts the events that a webhook delivers to
+// those matching all of its set fields. Fields only applies to
+// events with the EventChanged action.
+type WebhookFilter struct {
+	ResourceType    string      `json:"resource_type,omitempty"`
+	ResourceSubtype string      `json:"resource_subtype,omitempty"`
+	Action          EventAction `json:"action,omitempty"`
+	Fields          []string    `json:"fields,omitempty"`
+}
+
+type WebhookRequest struct {
+	// Resource is the gid of the object to receive events for.
+	Resource string `json:"resource"`
+
+	// Target is the https URL that events will be delivered to.
+	// It must complete the handshake, see WebhookHandler, before
+	// CreateWebhook returns.
+	Target string `json:"target"`
+
+	Filters []*WebhookFilter `json:"-"`
+}
+
+var (
+	errNilWebhookRequest = errors.New("expecting a non-nil webhookRequest")
+	errEmptyResource     = errors.New("expecting a non-empty resource")
+	errEmptyTarget       = errors.New("expecting a non-empty target")
+	errEmptyWebhookID    = errors.New("expecting a non-empty webhookID")
+	errNilWebhookQuery   = errors.New("expecting a non-nil webhookQuery")
+)
+
+func (wreq *WebhookRequest) Validate() error {
+	if wreq == nil {
+		return errNilWebhookRequest
+	}
+	if strings.TrimSpace(wreq.Resource) == "" {
+		return errEmptyResource
+	}
+	if strings.TrimSpace(wreq.Target) == "" {
+		return errEmptyTarget
+	}
+	return nil
+}
+
+func (wreq *WebhookRequest) toURLValues() (url.Values, error) {
+	qs, err := otils.ToURLValues(wreq)
+	if err != nil {
+		return nil, err
+	}
+	for i, filter := range wreq.Filters {
+		prefix := fmt.Sprintf("filters[%d]", i)
+		if filter.ResourceType != "" {
+			qs.Set(prefix+"[resource_type]", filter.ResourceType)
+		}
+		if filter.ResourceSubtype != "" {
+			qs.Set(prefix+"[resource_subtype]", filter.ResourceSubtype)
+		}
+		if filter.Action != "" {
+			qs.Set(prefix+"[action]", string(filter.Action))
+		}
+		if len(filter.Fields) > 0 {
+			qs.Set(prefix+"[fields]", strings.Join(filter.Fields, ","))
+		}
+	}
+	return qs, nil
+}
+
+type webhookWrap struct {
+	Webhook *Webhook `json:"data"`
+}
+
+func parseOutWebhookFromData(blob []byte) (*Webhook, error) {
+	ww := new(webhookWrap)
+	if err := json.Unmarshal(blob, ww); err != nil {
+		return nil, err
+	}
+	return ww.Webhook, nil
+}
+
+// CreateWebhook registers a webhook that delivers the events of a
+// resource to a target URL. Asana performs the handshake with the
+// target before responding so the target must already be serving.
+func (c *Client) CreateWebhook(wreq *WebhookRequest) (*Webhook, error) {
+	if err := wreq.Validate(); err != nil {
+		return nil, err
+	}
+	qs, err := wreq.toURLValues()
+	if err != nil {
+		return nil, err
+	}
+	slurp, err := c.doFormReq("POST", "/webhooks", qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutWebhookFromData(slurp)
+}
+
+func (c *Client) FindWebhookByID(webhookID string) (*Webhook, error) {
+	webhookID = strings.TrimSpace(webhookID)
+	if webhookID == "" {
+		return nil, errEmptyWebhookID
+	}
+	fullURL := fmt.Sprintf("%s/webhooks/%s", baseURL, webhookID)
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutWebhookFromData(slurp)
+}
+
+func (c *Client) DeleteWebhook(webhookID string) error {
+	webhookID = strings.TrimSpace(webhookID)
+	if webhookID == "" {
+		return errEmptyWebhookID
+	}
+	fullURL := fmt.Sprintf("%s/webhooks/%s", baseURL, webhookID)
+	req, err := http.NewRequest("DELETE", fullURL, nil)
+	if err != nil {
+		return err
+	}
+	_, _, err = c.doAuthReqThenSlurpBody(req)
+	return err
+}
+
+type WebhookQuery struct {
+	// WorkspaceID is required.
+	WorkspaceID string `json:"workspace"`
+
+	// ResourceID if set, only lists the webhooks for that resource.
+	ResourceID string `json:"resource,omitempty"`
+}
+
+type WebhooksPage struct {
+	Webhooks []*Webhook `json:"data"`
+	Err      error
+}
+
+type webhooksPager struct {
+	WebhooksPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+// ListWebhooks pages through the webhooks in a workspace,
+// optionally only those for a specific resource.
+func (c *Client) ListWebhooks(wq *WebhookQuery) (pagesChan chan *WebhooksPage, cancelChan chan<- bool, err error) {
+	if wq == nil {
+		return nil, nil, errNilWebhookQuery
+	}
+	if strings.TrimSpace(wq.WorkspaceID) == "" {
+		return nil, nil, errEmptyWorkspace
+	}
+	qs, err := otils.ToURLValues(wq)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagesChan = make(chan *WebhooksPage)
+	path := fmt.Sprintf("/webhooks?%s", qs.Encode())
+	cancel := c.sendPages(pagesChan, path, func(slurp []byte) (interface{}, string, error) {
+		pager := new(webhooksPager)
+		if err := json.Unmarshal(slurp, pager); err != nil {
+			return nil, "", err
+		}
+		return &pager.WebhooksPage, pager.NextPage.nextPath(), nil
+	})
+	return pagesChan, cancel, nil
+}
@@ -0,0 +1,292 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+const (
+	hookSecretHeader    = "X-Hook-Secret"
+	hookSignatureHeader = "X-Hook-Signature"
+
+	// maxWebhookBodyBytes bounds the size of
+	// a delivery that the handler will read.
+	maxWebhookBodyBytes = 10 << 20
+)
+
+// ErrNoWebhookSecret is returned by a WebhookSecretStore
+// that has no secret saved for the requested key.
+var ErrNoWebhookSecret = errors.New("no webhook secret was saved for this key")
+
+// WebhookSecretStore persists the secrets that Asana hands out
+// during webhook handshakes so that the signatures of later
+// deliveries can be verified, even across restarts.
+type WebhookSecretStore interface {
+	SaveSecret(key string, secret []byte) error
+
+	// Secret returns ErrNoWebhookSecret if no secret was saved for key.
+	Secret(key string) ([]byte, error)
+}
+
+type memorySecretStore struct {
+	sync.RWMutex
+	secrets map[string][]byte
+}
+
+// NewMemorySecretStore returns a WebhookSecretStore that keeps
+// secrets in memory. Secrets are lost when the process exits.
+func NewMemorySecretStore() WebhookSecretStore {
+	return &memorySecretStore{secrets: make(map[string][]byte)}
+}
+
+func (mss *memorySecretStore) SaveSecret(key string, secret []byte) error {
+	mss.Lock()
+	defer mss.Unlock()
+	mss.secrets[key] = append([]byte(nil), secret...)
+	return nil
+}
+
+func (mss *memorySecretStore) Secret(key string) ([]byte, error) {
+	mss.RLock()
+	defer mss.RUnlock()
+	secret, ok := mss.secrets[key]
+	if !ok {
+		return nil, ErrNoWebhookSecret
+	}
+	return secret, nil
+}
+
+// WebhookDelivery is the payload of a single webhook request.
+type WebhookDelivery struct {
+	Events []*Event `json:"events"`
+}
+
+type eventRoute struct {
+	resourceType string
+	action       EventAction
+	fn           func(*Event) error
+}
+
+// WebhookHandler is an http.Handler that receives webhook deliveries.
+// It completes the X-Hook-Secret handshake, see ExpectHandshake for
+// which handshakes are accepted, rejects deliveries whose
+// X-Hook-Signature does not match the saved secret, and dispatches
+// each event to the callbacks registered with Handle and to OnEvent.
+//
+// A callback returning an error makes the handler stop and respond
+// with a 500 status code so that Asana retries the delivery later.
+// Asana then redelivers every event of the delivery, including those
+// that were handled before the failing one, and the handler does not
+// deduplicate them. Delivery is therefore at least once: callbacks
+// must be idempotent, for example by keying the work that they do on
+// the resource gid, the action and the created_at of the event.
+type WebhookHandler struct {
+	// Secrets stores the handshake secrets. It must be set.
+	Secrets WebhookSecretStore
+
+	// KeyFunc returns the key under which the secret for the
+	// webhook that req was delivered for is stored. It defaults
+	// to the request path, which is correct if every webhook
+	// has its own target path.
+	KeyFunc func(req *http.Request) string
+
+	// OnEvent if set, is invoked for every event
+	// after the callbacks registered with Handle.
+	OnEvent func(*Event) error
+
+	routes []*eventRoute
+
+	mu sync.Mutex
+	// expected counts the pending handshakes of each key.
+	expected map[string]int
+}
+
+var errNilSecretStore = errors.New("expecting a non-nil webhook secret store")
+
+// Handle registers fn to be invoked for the events about resources of
+// resourceType, such as "task", and with the given action. A blank
+// resourceType or action matches any value. Handle must not be called
+// concurrently with ServeHTTP.
+func (wh *WebhookHandler) Handle(resourceType string, action EventAction, fn func(*Event) error) {
+	wh.routes = append(wh.routes, &eventRoute{resourceType: resourceType, action: action, fn: fn})
+}
+
+// ExpectHandshake allows a handshake for key to replace a secret that
+// was already saved, such as when recreating the webhook for a target.
+// Call it before CreateWebhook and call done once CreateWebhook returns.
+// Without it only the first handshake for a key is accepted, so that
+// no one else can swap in their own secret and forge deliveries.
+func (wh *WebhookHandler) ExpectHandshake(key string) (done func()) {
+	wh.mu.Lock()
+	defer wh.mu.Unlock()
+	if wh.expected == nil {
+		wh.expected = make(map[string]int)
+	}
+	wh.expected[key]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			wh.mu.Lock()
+			defer wh.mu.Unlock()
+			if wh.expected[key]--; wh.expected[key] <= 0 {
+				delete(wh.expected, key)
+			}
+		})
+	}
+}
+
+func (wh *WebhookHandler) handshakeExpected(key string) bool {
+	wh.mu.Lock()
+	defer wh.mu.Unlock()
+	return wh.expected[key] > 0
+}
+
+// acceptHandshake reports whether a handshake for key may save
+// a secret: if one is expected or no secret was saved yet.
+func (wh *WebhookHandler) acceptHandshake(key string) (bool, error) {
+	if wh.handshakeExpected(key) {
+		return true, nil
+	}
+	_, err := wh.Secrets.Secret(key)
+	switch err {
+	case ErrNoWebhookSecret:
+		return true, nil
+	case nil:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+func (wh *WebhookHandler) key(req *http.Request) string {
+	if wh.KeyFunc != nil {
+		return wh.KeyFunc(req)
+	}
+	return req.URL.Path
+}
+
+func (wh *WebhookHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" {
+		http.Error(rw, "only POST is accepted", http.StatusMethodNotAllowed)
+		return
+	}
+	if wh.Secrets == nil {
+		http.Error(rw, errNilSecretStore.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	key := wh.key(req)
+	if secret := req.Header.Get(hookSecretHeader); secret != "" {
+		accept, err := wh.acceptHandshake(key)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !accept {
+			http.Error(rw, "unexpected handshake", http.StatusForbidden)
+			return
+		}
+		if err := wh.Secrets.SaveSecret(key, []byte(secret)); err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rw.Header().Set(hookSecretHeader, secret)
+		rw.WriteHeader(http.StatusOK)
+		return
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(req.Body, maxWebhookBodyBytes))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	secret, err := wh.Secrets.Secret(key)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if !VerifyWebhookSignature(secret, body, req.Header.Get(hookSignatureHeader)) {
+		http.Error(rw, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	delivery := new(WebhookDelivery)
+	if err := json.Unmarshal(body, delivery); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := wh.dispatch(delivery); err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.WriteHeader(http.StatusOK)
+}
+
+// dispatch hands the events to the callbacks in order and stops
+// at the first error, which reports how many events were handled.
+func (wh *WebhookHandler) dispatch(delivery *WebhookDelivery) error {
+	for i, event := range delivery.Events {
+		if event == nil {
+			continue
+		}
+		for _, route := range wh.routes {
+			if route.resourceType != "" && route.resourceType != event.ResourceType() {
+				continue
+			}
+			if route.action != "" && route.action != event.Action {
+				continue
+			}
+			if err := route.fn(event); err != nil {
+				return dispatchError(i, len(delivery.Events), err)
+			}
+		}
+		if wh.OnEvent != nil {
+			if err := wh.OnEvent(event); err != nil {
+				return dispatchError(i, len(delivery.Events), err)
+			}
+		}
+	}
+	return nil
+}
+
+func dispatchError(i, n int, err error) error {
+	return fmt.Errorf("event %d of %d: %v; the whole delivery will be retried", i+1, n, err)
+}
+
+// VerifyWebhookSignature reports whether signature, the hex encoded
+// value of the X-Hook-Signature header, is the HMAC-SHA256 of body
+// keyed by the secret received during the webhook's handshake.
+func VerifyWebhookSignature(secret, body []byte, signature string) bool {
+	got, err := hex.DecodeString(signature)
+	if err != nil || len(got) == 0 {
+		return false
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,263 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestWebhookHandler(t *testing.T) {
+	var received []string
+	handler := &asana.WebhookHandler{Secrets: asana.NewMemorySecretStore()}
+	handler.Handle("task", asana.EventChanged, func(e *asana.Event) error {
+		received = append(received, "task-changed:"+e.Resource.GID+":"+e.Change.Field)
+		return nil
+	})
+	handler.Handle("story", "", func(e *asana.Event) error {
+		if e.Resource.GID == "fail" {
+			return errors.New("failed to process the story")
+		}
+		received = append(received, "story:"+e.Resource.GID)
+		return nil
+	})
+	handler.OnEvent = func(e *asana.Event) error {
+		received = append(received, "any:"+e.Resource.GID)
+		return nil
+	}
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	secret := []byte("the-secret-from-asana")
+	hookURL := server.URL + "/hooks/project-1"
+
+	// 1. The handshake.
+	req, _ := http.NewRequest("POST", hookURL, nil)
+	req.Header.Set("X-Hook-Secret", string(secret))
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("handshake: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("handshake: got status %d", res.StatusCode)
+	}
+	if got := res.Header.Get("X-Hook-Secret"); got != string(secret) {
+		t.Fatalf("handshake: got echoed secret %q want %q", got, secret)
+	}
+
+	validBody, err := ioutil.ReadFile("./testdata/webhook-delivery.json")
+	if err != nil {
+		t.Fatalf("reading the delivery: %v", err)
+	}
+	failingBody := []byte(`{"events": [{"action": "added", "resource": {"gid": "fail", "resource_type": "story"}}]}`)
+
+	tests := [...]struct {
+		path       string
+		body       []byte
+		signature  string
+		wantStatus int
+		wantEvents []string
+	}{
+		0: {
+			path:       "/hooks/project-1",
+			body:       validBody,
+			signature:  sign(secret, validBody),
+			wantStatus: http.StatusOK,
+			wantEvents: []string{
+				"task-changed:1001:name", "any:1001",
+				"any:1002",
+				"story:402", "any:402",
+			},
+		},
+		1: {
+			path:       "/hooks/project-1",
+			body:       validBody,
+			signature:  sign([]byte("another-secret"), validBody),
+			wantStatus: http.StatusUnauthorized,
+		},
+		2: {
+			path:       "/hooks/project-1",
+			body:       validBody,
+			signature:  "",
+			wantStatus: http.StatusUnauthorized,
+		},
+		3: {
+			// No handshake was performed for this path.
+			path:       "/hooks/project-2",
+			body:       validBody,
+			signature:  sign(secret, validBody),
+			wantStatus: http.StatusUnauthorized,
+		},
+		4: {
+			path:       "/hooks/project-1",
+			body:       failingBody,
+			signature:  sign(secret, failingBody),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for i, tt := range tests {
+		received = nil
+		req, _ := http.NewRequest("POST", server.URL+tt.path, bytes.NewReader(tt.body))
+		req.Header.Set("X-Hook-Signature", tt.signature)
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Errorf("#%d: got err: %v", i, err)
+			continue
+		}
+		res.Body.Close()
+
+		if res.StatusCode != tt.wantStatus {
+			t.Errorf("#%d: gotStatus=%d wantStatus=%d", i, res.StatusCode, tt.wantStatus)
+		}
+		if !reflect.DeepEqual(received, tt.wantEvents) {
+			t.Errorf("#%d:\ngotEvents:  %v\nwantEvents: %v", i, received, tt.wantEvents)
+		}
+	}
+}
+
+func TestWebhookPartialFailureIsRedelivered(t *testing.T) {
+	secrets := asana.NewMemorySecretStore()
+	secret := []byte("the-secret-from-asana")
+	if err := secrets.SaveSecret("/hooks/project-1", secret); err != nil {
+		t.Fatalf("saving the secret: %v", err)
+	}
+
+	var handled []string
+	failures := 1
+	handler := &asana.WebhookHandler{Secrets: secrets}
+	handler.Handle("story", "", func(e *asana.Event) error {
+		if e.Resource.GID == "flaky" && failures > 0 {
+			failures--
+			return errors.New("temporarily unavailable")
+		}
+		handled = append(handled, e.Resource.GID)
+		return nil
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	body := []byte(`{"events": [
+		{"action": "added", "resource": {"gid": "401", "resource_type": "story"}},
+		{"action": "added", "resource": {"gid": "flaky", "resource_type": "story"}},
+		{"action": "added", "resource": {"gid": "403", "resource_type": "story"}}
+	]}`)
+	deliver := func() (int, string) {
+		req, _ := http.NewRequest("POST", server.URL+"/hooks/project-1", bytes.NewReader(body))
+		req.Header.Set("X-Hook-Signature", sign(secret, body))
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("delivering: %v", err)
+		}
+		defer res.Body.Close()
+		msg, _ := ioutil.ReadAll(res.Body)
+		return res.StatusCode, string(msg)
+	}
+
+	// The first attempt stops at the failing event.
+	status, msg := deliver()
+	if status != http.StatusInternalServerError || !strings.Contains(msg, "event 2 of 3") {
+		t.Errorf("first attempt: got status %d and %q", status, msg)
+	}
+	if want := []string{"401"}; !reflect.DeepEqual(handled, want) {
+		t.Errorf("first attempt:\ngotHandled:  %v\nwantHandled: %v", handled, want)
+	}
+
+	// Asana retries the whole delivery so
+	// the first event is handled again.
+	if status, msg := deliver(); status != http.StatusOK {
+		t.Errorf("retry: got status %d and %q", status, msg)
+	}
+	if want := []string{"401", "401", "flaky", "403"}; !reflect.DeepEqual(handled, want) {
+		t.Errorf("retry:\ngotHandled:  %v\nwantHandled: %v", handled, want)
+	}
+}
+
+func TestWebhookHandshakeCannotReplaceSecret(t *testing.T) {
+	handler := &asana.WebhookHandler{Secrets: asana.NewMemorySecretStore()}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	hookURL := server.URL + "/hooks/project-1"
+	handshake := func(secret string) int {
+		req, _ := http.NewRequest("POST", hookURL, nil)
+		req.Header.Set("X-Hook-Secret", secret)
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("handshake: %v", err)
+		}
+		res.Body.Close()
+		return res.StatusCode
+	}
+	deliver := func(secret string) int {
+		body := []byte(`{"events": []}`)
+		req, _ := http.NewRequest("POST", hookURL, bytes.NewReader(body))
+		req.Header.Set("X-Hook-Signature", sign([]byte(secret), body))
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("delivery: %v", err)
+		}
+		res.Body.Close()
+		return res.StatusCode
+	}
+
+	if got := handshake("asana-secret"); got != http.StatusOK {
+		t.Fatalf("first handshake: got status %d", got)
+	}
+
+	// An attacker tries to swap in their own secret.
+	if got := handshake("attacker-secret"); got != http.StatusForbidden {
+		t.Errorf("second handshake: gotStatus=%d wantStatus=%d", got, http.StatusForbidden)
+	}
+	if got := deliver("attacker-secret"); got != http.StatusUnauthorized {
+		t.Errorf("forged delivery: gotStatus=%d wantStatus=%d", got, http.StatusUnauthorized)
+	}
+	if got := deliver("asana-secret"); got != http.StatusOK {
+		t.Errorf("genuine delivery: gotStatus=%d wantStatus=%d", got, http.StatusOK)
+	}
+
+	// Recreating the webhook replaces the secret while expected.
+	done := handler.ExpectHandshake("/hooks/project-1")
+	if got := handshake("new-asana-secret"); got != http.StatusOK {
+		t.Errorf("expected handshake: got status %d", got)
+	}
+	done()
+	if got := handshake("attacker-secret"); got != http.StatusForbidden {
+		t.Errorf("handshake after done: gotStatus=%d wantStatus=%d", got, http.StatusForbidden)
+	}
+	if got := deliver("new-asana-secret"); got != http.StatusOK {
+		t.Errorf("delivery with the new secret: gotStatus=%d wantStatus=%d", got, http.StatusOK)
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type Workspace struct {
+	Name string `json:"name"`
+	ID   int64  `json:"id"`
+	GID  string `json:"gid,omitempty"`
+
+	// IsOrganization is set for workspaces that are
+	// organizations, which are tied to email domains.
+	IsOrganization bool `json:"is_organization,omitempty"`
+
+	EmailDomains []string `json:"email_domains,omitempty"`
+}
+
+type WorkspaceRequest struct {
+	WorkspaceID string `json:"-"`
+
+	// Name is the only field of a workspace that can be changed.
+	Name string `json:"name"`
+}
+
+var (
+	errNilWorkspaceRequest = errors.New("expecting a non-nil workspaceRequest")
+	errEmptyWorkspaceName  = errors.New("expecting a non-empty workspace name")
+)
+
+func (wreq *WorkspaceRequest) Validate() error {
+	if wreq == nil {
+		return errNilWorkspaceRequest
+	}
+	if strings.TrimSpace(wreq.WorkspaceID) == "" {
+		return errEmptyWorkspace
+	}
+	if strings.TrimSpace(wreq.Name) == "" {
+		return errEmptyWorkspaceName
+	}
+	return nil
+}
+
+type workspaceWrap struct {
+	Workspace *Workspace `json:"data"`
+}
+
+func parseOutWorkspaceFromData(blob []byte) (*Workspace, error) {
+	ww := new(workspaceWrap)
+	if err := json.Unmarshal(blob, ww); err != nil {
+		return nil, err
+	}
+	return ww.Workspace, nil
+}
+
+func (c *Client) FindWorkspaceByID(workspaceID string) (*Workspace, error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" {
+		return nil, errEmptyWorkspace
+	}
+	fullURL := fmt.Sprintf("%s/workspaces/%s", baseURL, workspaceID)
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	slurp, _, err := c.doAuthReqThenSlurpBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutWorkspaceFromData(slurp)
+}
+
+func (c *Client) UpdateWorkspace(wreq *WorkspaceRequest) (*Workspace, error) {
+	if err := wreq.Validate(); err != nil {
+		return nil, err
+	}
+	qs := make(url.Values)
+	qs.Set("name", wreq.Name)
+	path := fmt.Sprintf("/workspaces/%s", strings.TrimSpace(wreq.WorkspaceID))
+	slurp, err := c.doFormReq("PUT", path, qs)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutWorkspaceFromData(slurp)
+}
+
+// AddUserToWorkspace adds the user, identified by gid, email
+// address or "me", to the workspace and returns the user.
+func (c *Client) AddUserToWorkspace(workspaceID, userID string) (*User, error) {
+	slurp, err := c.changeWorkspaceUser("addUser", workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutUserFromData(slurp)
+}
+
+// RemoveUserFromWorkspace removes the user, identified by
+// gid, email address or "me", from the workspace.
+func (c *Client) RemoveUserFromWorkspace(workspaceID, userID string) error {
+	_, err := c.changeWorkspaceUser("removeUser", workspaceID, userID)
+	return err
+}
+
+func (c *Client) changeWorkspaceUser(action, workspaceID, userID string) ([]byte, error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" {
+		return nil, errEmptyWorkspace
+	}
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, errEmptyUserID
+	}
+	qs := make(url.Values)
+	qs.Set("user", userID)
+	path := fmt.Sprintf("/workspaces/%s/%s", workspaceID, action)
+	return c.doFormReq("POST", path, qs)
+}
+
+// WorkspaceMembership is the access that a user has to a workspace.
+type WorkspaceMembership struct {
+	ID  int64  `json:"id,omitempty"`
+	GID string `json:"gid,omitempty"`
+
+	User      *NamedAndIDdEntity `json:"user,omitempty"`
+	Workspace *NamedAndIDdEntity `json:"workspace,omitempty"`
+
+	IsAdmin  bool `json:"is_admin,omitempty"`
+	IsActive bool `json:"is_active,omitempty"`
+	IsGuest  bool `json:"is_guest,omitempty"`
+}
+
+type WorkspaceMembershipsPage struct {
+	Memberships []*WorkspaceMembership `json:"data"`
+	Err         error
+}
+
+type workspaceMembershipsPager struct {
+	WorkspaceMembershipsPage
+
+	NextPage *pageToken `json:"next_page,omitempty"`
+}
+
+const workspaceMembershipOptFields = "user.name,workspace.name,is_admin,is_active,is_guest"
+
+func (c *Client) ListWorkspaceMemberships(workspaceID string) (pagesChan chan *WorkspaceMembershipsPage, cancelChan chan<- bool, err error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" {
+		return nil, nil, errEmptyWorkspace
+	}
+
+	startPath := fmt.Sprintf("/workspaces/%s/workspace_memberships?opt_fields=%s", workspaceID, workspaceMembershipOptFields)
+	return c.pageForWorkspaceMemberships(startPath)
+}
+
+// ListWorkspaceMembershipsForUser pages through the memberships of the
+// user, identified by gid, email address or "me", in all workspaces.
+func (c *Client) ListWorkspaceMembershipsForUser(userID string) (pagesChan chan *WorkspaceMembershipsPage, cancelChan chan<- bool, err error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, nil, errEmptyUserID
+	}
+
+	startPath := fmt.Sprintf("/users/%s/workspace_memberships?opt_fields=%s", url.PathEscape(userID), workspaceMembershipOptFields)
+	return c.pageForWorkspaceMemberships(startPath)
+}
+
+func (c *Client) pageForWorkspaceMemberships(path string) (pagesChan chan *WorkspaceMembershipsPage, cancelChan chan<- bool, err error) {
+	pagesChan = make(chan *WorkspaceMembershipsPage)
+	cancel := c.sendPages(pagesChan, path, func(slurp []byte) (interface{}, string, error) {
+		pager := new(workspaceMembershipsPager)
+		if err := json.Unmarshal(slurp, pager); err != nil {
+			return nil, "", err
+		}
+		return &pager.WorkspaceMembershipsPage, pager.NextPage.nextPath(), nil
+	})
+	return pagesChan, cancel, nil
+}
@@ -0,0 +1,224 @@
+// Copyright 2017 orijtech. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asana_test
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orijtech/asana/v1"
+)
+
+func TestFindWorkspaceByID(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: workspacesRoute})
+
+	if _, err := client.FindWorkspaceByID("  "); err == nil {
+		t.Errorf("expected an error for a blank workspaceID")
+	}
+
+	workspace, err := client.FindWorkspaceByID(workspaceID1)
+	if err != nil {
+		t.Fatalf("finding the workspace: %v", err)
+	}
+	want := &asana.Workspace{
+		GID:            workspaceID1,
+		Name:           "orijtech",
+		IsOrganization: true,
+		EmailDomains:   []string{"orijtech.com"},
+	}
+	if !reflect.DeepEqual(workspace, want) {
+		t.Errorf("got=%#v want=%#v", workspace, want)
+	}
+}
+
+func TestAddAndRemoveWorkspaceUser(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: workspacesRoute})
+
+	tests := [...]struct {
+		workspaceID string
+		userID      string
+		wantErr     bool
+	}{
+		0: {workspaceID: "", userID: "ada@orijtech.com", wantErr: true},
+		1: {workspaceID: workspaceID1, userID: " ", wantErr: true},
+		2: {workspaceID: workspaceID1, userID: "ada@orijtech.com"},
+		3: {workspaceID: "workspace-2", userID: "ada@orijtech.com", wantErr: true},
+	}
+
+	for i, tt := range tests {
+		user, err := client.AddUserToWorkspace(tt.workspaceID, tt.userID)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: add: wanted non-nil error", i)
+			}
+		} else if err != nil {
+			t.Errorf("#%d: add: got err: %v", i, err)
+		} else if user.Email != tt.userID {
+			t.Errorf("#%d: add: gotEmail=%q wantEmail=%q", i, user.Email, tt.userID)
+		}
+
+		err = client.RemoveUserFromWorkspace(tt.workspaceID, tt.userID)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: remove: wanted non-nil error", i)
+			}
+		} else if err != nil {
+			t.Errorf("#%d: remove: got err: %v", i, err)
+		}
+	}
+}
+
+func TestListWorkspaceMemberships(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: workspacesRoute})
+
+	pagesChan, _, err := client.ListWorkspaceMemberships(workspaceID1)
+	if err != nil {
+		t.Fatalf("listing memberships: %v", err)
+	}
+	var guests []string
+	for page := range pagesChan {
+		if page.Err != nil {
+			t.Fatalf("page err: %v", page.Err)
+		}
+		for _, membership := range page.Memberships {
+			if membership.IsGuest {
+				guests = append(guests, membership.User.Name)
+			}
+		}
+	}
+	if want := []string{"Contractor"}; !reflect.DeepEqual(guests, want) {
+		t.Errorf("gotGuests=%v wantGuests=%v", guests, want)
+	}
+}
+
+func TestListMyWorkspaces(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: workspacesRoute})
+
+	pagesChan, err := client.ListMyWorkspaces()
+	if err != nil {
+		t.Fatalf("listing workspaces: %v", err)
+	}
+	var gotGIDs []string
+	for page := range pagesChan {
+		if page.Err != nil {
+			t.Fatalf("page err: %v", page.Err)
+		}
+		for _, workspace := range page.Workspaces {
+			gotGIDs = append(gotGIDs, workspace.GID)
+		}
+	}
+	if want := []string{workspaceID1, "workspace-2"}; !reflect.DeepEqual(gotGIDs, want) {
+		t.Errorf("gotGIDs=%v wantGIDs=%v", gotGIDs, want)
+	}
+}
+
+func TestCancelPagingWithoutDraining(t *testing.T) {
+	client, err := asana.NewClient(paToken1)
+	if err != nil {
+		t.Fatalf("initializing the client: %v", err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: workspacesRoute})
+
+	before := runtime.NumGoroutine()
+	pagesChan, cancelChan, err := client.ListAllMyWorkspaces()
+	if err != nil {
+		t.Fatalf("listing workspaces: %v", err)
+	}
+	if page := <-pagesChan; page == nil || page.Err != nil {
+		t.Fatalf("unexpected first page: %#v", page)
+	}
+	// Stop receiving without draining the remaining pages.
+	cancelChan <- true
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("the pager goroutine did not exit: %d goroutines, want %d", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func (b *backend) workspacesRoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Path == "/api/1.0/workspaces" {
+		if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		body := fmt.Sprintf(`{"data": [{"gid": %q, "name": "orijtech"}],
+			"next_page": {"offset": "page-2", "path": "/workspaces?offset=page-2"}}`, workspaceID1)
+		if req.URL.Query().Get("offset") == "page-2" {
+			body = `{"data": [{"gid": "workspace-2", "name": "odeke-em"}], "next_page": null}`
+		}
+		return makeResp("200 OK", http.StatusOK, nopCloser(body)), nil
+	}
+
+	prefix := "/api/1.0/workspaces/" + workspaceID1
+	if !strings.HasPrefix(req.URL.Path, prefix) {
+		return makeResp("workspace not found", http.StatusNotFound, nil), nil
+	}
+
+	switch action := strings.TrimPrefix(req.URL.Path, prefix); action {
+	case "":
+		if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		body := fmt.Sprintf(`{"data": {"gid": %q, "name": "orijtech", "is_organization": true, "email_domains": ["orijtech.com"]}}`, workspaceID1)
+		return makeResp("200 OK", http.StatusOK, nopCloser(body)), nil
+
+	case "/workspace_memberships":
+		if badAuthResp, err := b.checkAuthorization(req, "GET"); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		return makeRespFromFile(fmt.Sprintf("./testdata/workspace-memberships-%s.json", workspaceID1))
+
+	case "/addUser", "/removeUser":
+		if badAuthResp, err := b.checkAuthorization(req, "POST"); err != nil || badAuthResp != nil {
+			return badAuthResp, err
+		}
+		if err := req.ParseForm(); err != nil {
+			return makeResp(err.Error(), http.StatusBadRequest, nil), nil
+		}
+		email := req.PostForm.Get("user")
+		if action == "/removeUser" {
+			return makeResp("200 OK", http.StatusOK, nopCloser(`{"data": {}}`)), nil
+		}
+		body := fmt.Sprintf(`{"data": {"gid": "8", "name": "Ada", "email": %q}}`, email)
+		return makeResp("200 OK", http.StatusOK, nopCloser(body)), nil
+
+	default:
+		return unknownRouteResp, nil
+	}
+}